@@ -0,0 +1,237 @@
+package assertion
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/keithpaterson/postal/config"
+	"github.com/keithpaterson/postal/jsonpath"
+	"github.com/keithpaterson/postal/statusmatch"
+	"github.com/keithpaterson/postal/trace"
+)
+
+var (
+	ErrNoTimings = errors.New("timings are not available")
+)
+
+// Result is the outcome of a single assertion
+type Result struct {
+	Name     string
+	Passed   bool
+	Expected string // the description of the assertion
+	Message  string // why the assertion failed (i.e. the actual value); empty when it passed
+}
+
+// Summary holds the results of every assertion
+type Summary struct {
+	Results []Result
+}
+
+// Evaluate checks the response against each assertion; body is the response body, which the caller has already read.
+func Evaluate(asserts []config.AssertConfig, resp *http.Response, body []byte) *Summary {
+	summary := &Summary{}
+	for _, assert := range asserts {
+		result := Result{Name: assert.Name, Passed: true, Expected: Description(assert)}
+		if result.Name == "" {
+			result.Name = result.Expected
+		}
+		if err := check(assert, resp, body); err != nil {
+			result.Passed = false
+			result.Message = err.Error()
+		}
+		summary.Results = append(summary.Results, result)
+	}
+	return summary
+}
+
+// Failed returns the number of assertions that failed
+func (s *Summary) Failed() int {
+	failed := 0
+	for _, result := range s.Results {
+		if !result.Passed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Write writes one line per assertion followed by a summary
+func (s *Summary) Write(w io.Writer) error {
+	var b strings.Builder
+	for _, result := range s.Results {
+		if result.Passed {
+			fmt.Fprintf(&b, "PASS  %s\n", result.Name)
+		} else {
+			fmt.Fprintf(&b, "FAIL  %s: %s\n", result.Name, result.Message)
+		}
+	}
+	fmt.Fprintf(&b, "%d passed, %d failed\n", len(s.Results)-s.Failed(), s.Failed())
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Description returns a description of the assertion, e.g. `json data.id equals "ord-42"`
+func Description(assert config.AssertConfig) string {
+	parts := []string{assert.Check}
+	if assert.Expr != "" {
+		parts = append(parts, assert.Expr)
+	}
+	if assert.Exists != nil {
+		if *assert.Exists {
+			parts = append(parts, "exists")
+		} else {
+			parts = append(parts, "does not exist")
+		}
+	}
+	if assert.Equals != nil {
+		parts = append(parts, "equals", strconv.Quote(*assert.Equals))
+	}
+	if assert.Contains != "" {
+		parts = append(parts, "contains", strconv.Quote(assert.Contains))
+	}
+	if assert.Matches != "" {
+		parts = append(parts, "matches", strconv.Quote(assert.Matches))
+	}
+	if assert.Type != "" {
+		parts = append(parts, "is", assert.Type)
+	}
+	if assert.Min != nil {
+		parts = append(parts, ">=", formatNumber(*assert.Min))
+	}
+	if assert.Max != nil {
+		parts = append(parts, "<=", formatNumber(*assert.Max))
+	}
+	return strings.Join(parts, " ")
+}
+
+func check(assert config.AssertConfig, resp *http.Response, body []byte) error {
+	switch assert.Check {
+	case "status":
+		return checkStatus(assert.Expr, resp.StatusCode)
+	case "header":
+		values := resp.Header.Values(assert.Expr)
+		if len(values) == 0 {
+			return checkMissing(assert)
+		}
+		return checkValue(assert, strings.Join(values, ","))
+	case "json":
+		return checkJSON(assert, body)
+	case "body":
+		return checkValue(assert, string(body))
+	case "size":
+		return checkRange(assert, float64(len(body)), "bytes")
+	case "latency":
+		t := trace.FromResponse(resp)
+		if t == nil {
+			return ErrNoTimings
+		}
+		return checkRange(assert, float64(t.Timings().Total)/float64(time.Millisecond), "ms")
+	}
+	return fmt.Errorf("unsupported check '%s'", assert.Check)
+}
+
+func checkStatus(spec string, code int) error {
+	matcher, err := statusmatch.Parse(spec)
+	if err != nil {
+		return err
+	}
+	if !matcher.Match(code) {
+		return fmt.Errorf("got %d", code)
+	}
+	return nil
+}
+
+func checkMissing(assert config.AssertConfig) error {
+	if assert.Exists != nil && !*assert.Exists {
+		return nil
+	}
+	return errors.New("not found")
+}
+
+func checkJSON(assert config.AssertConfig, body []byte) error {
+	value, err := jsonpath.Lookup(body, assert.Expr)
+	if errors.Is(err, jsonpath.ErrPathNotFound) {
+		return checkMissing(assert)
+	}
+	if err != nil {
+		return err
+	}
+	if assert.Type != "" {
+		if actual := typeOf(value); actual != assert.Type {
+			return fmt.Errorf("got %s", actual)
+		}
+	}
+	text, err := jsonpath.String(value)
+	if err != nil {
+		return err
+	}
+	return checkValue(assert, text)
+}
+
+// checkValue applies the comparisons to a value that exists
+func checkValue(assert config.AssertConfig, value string) error {
+	if assert.Exists != nil && !*assert.Exists {
+		return fmt.Errorf("got %s", preview(value))
+	}
+	if assert.Equals != nil && value != *assert.Equals {
+		return fmt.Errorf("got %s", preview(value))
+	}
+	if assert.Contains != "" && !strings.Contains(value, assert.Contains) {
+		return fmt.Errorf("got %s", preview(value))
+	}
+	if assert.Matches != "" {
+		re, err := regexp.Compile(assert.Matches)
+		if err != nil {
+			return err
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("got %s", preview(value))
+		}
+	}
+	return nil
+}
+
+func checkRange(assert config.AssertConfig, value float64, unit string) error {
+	if (assert.Min != nil && value < *assert.Min) || (assert.Max != nil && value > *assert.Max) {
+		return fmt.Errorf("got %s %s", formatNumber(value), unit)
+	}
+	return nil
+}
+
+func typeOf(value any) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case json.Number, float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// preview quotes the value, shortening long values (e.g. bodies) so that the report stays readable
+func preview(value string) string {
+	const maxPreview = 60
+	if len(value) > maxPreview {
+		return strconv.Quote(value[:maxPreview]) + "..."
+	}
+	return strconv.Quote(value)
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,16 @@
+package assertion_test
+
+import (
+	"testing"
+
+	"github.com/keithpaterson/postal/logging"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestAssertion(t *testing.T) {
+	logging.Disable()
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Assertion Suite")
+}
@@ -0,0 +1,111 @@
+package assertion
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/keithpaterson/postal/config"
+	"github.com/keithpaterson/postal/trace"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func newAssertResponse() *http.Response {
+	req, _ := http.NewRequest("GET", "http://test.io", nil)
+	t := trace.New()
+	t.MarkResponse()
+	t.MarkDone()
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Request:    trace.WithTrace(req, t),
+	}
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+var _ = Describe("Assertion", func() {
+	body := []byte(`{"data":{"id":"ord-42","count":3,"items":[1,2],"ok":true,"none":null}}`)
+
+	DescribeTable("Evaluate",
+		func(assert config.AssertConfig, expectPassed bool, expectMessage string) {
+			// Act
+			report := Evaluate([]config.AssertConfig{assert}, newAssertResponse(), body)
+
+			// Assert
+			Expect(report.Results).To(HaveLen(1))
+			Expect(report.Results[0].Passed).To(Equal(expectPassed))
+			Expect(report.Results[0].Message).To(Equal(expectMessage))
+		},
+		Entry("status", config.AssertConfig{Check: "status", Expr: "2xx"}, true, ""),
+		Entry("status fails", config.AssertConfig{Check: "status", Expr: "201"}, false, "got 200"),
+		Entry("header exists", config.AssertConfig{Check: "header", Expr: "content-type"}, true, ""),
+		Entry("header missing", config.AssertConfig{Check: "header", Expr: "X-Missing"}, false, "not found"),
+		Entry("header does not exist", config.AssertConfig{Check: "header", Expr: "X-Missing", Exists: ptr(false)}, true, ""),
+		Entry("header contains", config.AssertConfig{Check: "header", Expr: "Content-Type", Contains: "json"}, true, ""),
+		Entry("header equals fails", config.AssertConfig{Check: "header", Expr: "Content-Type", Equals: ptr("text/plain")}, false, `got "application/json"`),
+		Entry("json equals", config.AssertConfig{Check: "json", Expr: "data.id", Equals: ptr("ord-42")}, true, ""),
+		Entry("json equals fails", config.AssertConfig{Check: "json", Expr: "data.id", Equals: ptr("ord-7")}, false, `got "ord-42"`),
+		Entry("json matches", config.AssertConfig{Check: "json", Expr: "data.id", Matches: `^ord-\d+$`}, true, ""),
+		Entry("json missing", config.AssertConfig{Check: "json", Expr: "data.missing"}, false, "not found"),
+		Entry("json exists fails", config.AssertConfig{Check: "json", Expr: "data.id", Exists: ptr(false)}, false, `got "ord-42"`),
+		Entry("json number", config.AssertConfig{Check: "json", Expr: "data.count", Type: "number", Equals: ptr("3")}, true, ""),
+		Entry("json array", config.AssertConfig{Check: "json", Expr: "data.items", Type: "array"}, true, ""),
+		Entry("json boolean", config.AssertConfig{Check: "json", Expr: "data.ok", Type: "boolean"}, true, ""),
+		Entry("json null", config.AssertConfig{Check: "json", Expr: "data.none", Type: "null"}, true, ""),
+		Entry("json type fails", config.AssertConfig{Check: "json", Expr: "data", Type: "array"}, false, "got object"),
+		Entry("body matches", config.AssertConfig{Check: "body", Matches: `"count":\d`}, true, ""),
+		Entry("body contains fails", config.AssertConfig{Check: "body", Contains: "nope"}, false, `got "{\"data\":{\"id\":\"ord-42\",\"count\":3,\"items\":[1,2],\"ok\":true,\"no"...`),
+		Entry("size", config.AssertConfig{Check: "size", Min: ptr(1.0), Max: ptr(1024.0)}, true, ""),
+		Entry("size fails", config.AssertConfig{Check: "size", Max: ptr(10.0)}, false, "got 70 bytes"),
+		Entry("latency", config.AssertConfig{Check: "latency", Max: ptr(60000.0)}, true, ""),
+	)
+
+	It("reports latency without timings", func() {
+		// Arrange
+		resp := newAssertResponse()
+		resp.Request = nil
+
+		// Act
+		report := Evaluate([]config.AssertConfig{{Check: "latency", Max: ptr(1.0)}}, resp, body)
+
+		// Assert
+		Expect(report.Results[0].Passed).To(BeFalse())
+		Expect(report.Results[0].Message).To(Equal(ErrNoTimings.Error()))
+	})
+
+	It("writes a report", func() {
+		// Arrange
+		asserts := []config.AssertConfig{
+			{Check: "status", Expr: "2xx"},
+			{Name: "order id", Check: "json", Expr: "data.id", Equals: ptr("ord-7")},
+		}
+		report := Evaluate(asserts, newAssertResponse(), body)
+		var buf bytes.Buffer
+
+		// Act
+		err := report.Write(&buf)
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Failed()).To(Equal(1))
+		Expect(buf.String()).To(Equal("PASS  status 2xx\nFAIL  order id: got \"ord-42\"\n1 passed, 1 failed\n"))
+	})
+
+	DescribeTable("Description",
+		func(assert config.AssertConfig, expect string) {
+			// Act
+			actual := Description(assert)
+
+			// Assert
+			Expect(actual).To(Equal(expect))
+		},
+		Entry("status", config.AssertConfig{Check: "status", Expr: ">=200,<300"}, "status >=200,<300"),
+		Entry("json", config.AssertConfig{Check: "json", Expr: "data.id", Equals: ptr("x"), Type: "string"}, `json data.id equals "x" is string`),
+		Entry("header", config.AssertConfig{Check: "header", Expr: "X-Test", Exists: ptr(false)}, "header X-Test does not exist"),
+		Entry("latency", config.AssertConfig{Check: "latency", Max: ptr(250.5)}, "latency <= 250.5"),
+	)
+})
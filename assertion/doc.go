@@ -0,0 +1,14 @@
+/*
+package assertion checks a response against the [[assert]] entries in the configuration.
+
+Every assertion is evaluated (a failure doesn't stop the others) and the results are written as a report:
+
+	PASS  status 2xx
+	FAIL  json data.id equals "ord-42": got "ord-7"
+	1 passed, 1 failed
+
+See [config.AssertConfig] for the checks that are available.
+Header, json and body checks can combine comparisons, e.g. contains = "json" and matches = "^application/";
+all of them must pass.
+*/
+package assertion
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/keithpaterson/postal/config"
+)
+
+var (
+	ErrInvalidAuthType = errors.New("invalid auth type")
+)
+
+// Signer adds authentication information to a fully-built request.
+type Signer interface {
+	Sign(req *http.Request, body []byte) error
+}
+
+type noneSigner struct{}
+
+// NewSigner returns the signer identified by the config data.
+func NewSigner(cfg config.AuthConfig) (Signer, error) {
+	switch cfg.AuthType() {
+	case config.AuthNone:
+		return noneSigner{}, nil
+	case config.AuthAWSSigV4:
+		return newSigV4Signer(cfg), nil
+	default:
+		return nil, fmt.Errorf("%w '%s'", ErrInvalidAuthType, cfg.Type)
+	}
+}
+
+func (noneSigner) Sign(_ *http.Request, _ []byte) error {
+	return nil
+}
@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"github.com/keithpaterson/postal/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Auth", func() {
+	DescribeTable("NewSigner",
+		func(authType string, expected any, expectErr error) {
+			// Act
+			signer, err := NewSigner(config.AuthConfig{Type: authType})
+
+			// Assert
+			if expectErr != nil {
+				Expect(err).To(MatchError(expectErr))
+				Expect(signer).To(BeNil())
+			} else {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(signer).To(BeAssignableToTypeOf(expected))
+			}
+		},
+		Entry("none", "none", noneSigner{}, nil),
+		Entry("aws-sigv4", "aws-sigv4", &sigV4Signer{}, nil),
+		Entry("invalid", "basic", nil, ErrInvalidAuthType),
+		Entry("empty", "", nil, ErrInvalidAuthType),
+	)
+})
@@ -0,0 +1,18 @@
+// package auth signs requests according to the config.AuthConfig structure.
+//
+// Signing happens after the request headers and body have been built, immediately before the request is sent:
+//
+//	signer, err := auth.NewSigner(cfg.Auth)
+//	err = signer.Sign(req, body)
+//
+// Supported signing schemes are:
+//
+//	"none"      : the request is not modified
+//	"aws-sigv4" : AWS Signature Version 4; adds the "X-Amz-Date" and "Authorization" headers
+//	              (and "X-Amz-Security-Token" when a session token is used).
+//	              For the "s3" service the "X-Amz-Content-Sha256" header is added as well.
+//
+// Separately, NewHMACSigner adds a webhook-style HMAC signature header according to the config.SigningConfig
+// structure.  The canonical string is built by resolving the signing template, which can use "${request:...}"
+// tokens to refer to the request body, method, path and headers.
+package auth
@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+
+	"github.com/keithpaterson/postal/config"
+	"github.com/keithpaterson/postal/jwt"
+	"github.com/keithpaterson/postal/resolver"
+)
+
+var (
+	ErrInvalidHMACAlgorithm = errors.New("invalid signing algorithm")
+	ErrInvalidHMACEncoding  = errors.New("invalid signing encoding")
+)
+
+type hmacSigner struct {
+	cfg *config.Config
+}
+
+// NewHMACSigner returns the signer for the [signing] section of the config.
+//
+// The full config is required because the canonical-string template can contain property tokens.
+func NewHMACSigner(cfg *config.Config) Signer {
+	if cfg.Signing.Header == "" {
+		return noneSigner{}
+	}
+	return &hmacSigner{cfg: cfg}
+}
+
+func (s *hmacSigner) Sign(req *http.Request, body []byte) error {
+	signing := s.cfg.Signing
+
+	newHash, err := s.hashFunc(signing.Algorithm)
+	if err != nil {
+		return err
+	}
+	secret, err := jwt.KeyData(signing.Secret)
+	if err != nil {
+		return fmt.Errorf("failed to load signing secret: %w", err)
+	}
+
+	canonical := resolver.NewResolver(s.cfg).WithRequest(req, body).Resolve(signing.Template)
+
+	mac := hmac.New(newHash, secret)
+	mac.Write([]byte(canonical))
+	signature := mac.Sum(nil)
+
+	var encoded string
+	switch signing.Encoding {
+	case "", "hex":
+		encoded = hex.EncodeToString(signature)
+	case "base64":
+		encoded = base64.StdEncoding.EncodeToString(signature)
+	default:
+		return fmt.Errorf("%w '%s'", ErrInvalidHMACEncoding, signing.Encoding)
+	}
+
+	req.Header.Set(signing.Header, signing.Prefix+encoded)
+	return nil
+}
+
+func (s *hmacSigner) hashFunc(algorithm string) (func() hash.Hash, error) {
+	switch algorithm {
+	case "hmac-sha1":
+		return sha1.New, nil
+	case "", "hmac-sha256":
+		return sha256.New, nil
+	case "hmac-sha512":
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("%w '%s'", ErrInvalidHMACAlgorithm, algorithm)
+	}
+}
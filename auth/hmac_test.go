@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/keithpaterson/postal/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func hmacConfig(template string, algorithm string, encoding string, prefix string) *config.Config {
+	cfg := config.NewConfig()
+	cfg.Properties["tenant"] = "acme"
+	cfg.Signing = config.SigningConfig{
+		Header:    "X-Signature",
+		Prefix:    prefix,
+		Template:  template,
+		Algorithm: algorithm,
+		Encoding:  encoding,
+		Secret:    "string:It's a Secret to Everybody",
+	}
+	return cfg
+}
+
+var _ = Describe("HMAC", func() {
+	var (
+		body = []byte("Hello, World!")
+	)
+
+	DescribeTable("Sign",
+		func(cfg *config.Config, expect string, expectErr error) {
+			// Arrange
+			req, err := http.NewRequest("POST", "https://hooks.example.com/receive?id=1", bytes.NewReader(body))
+			Expect(err).ToNot(HaveOccurred())
+			req.Header.Set("X-Timestamp", "1700000000")
+
+			// Act
+			err = NewHMACSigner(cfg).Sign(req, body)
+
+			// Assert
+			if expectErr != nil {
+				Expect(err).To(MatchError(expectErr))
+			} else {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(req.Header.Get("X-Signature")).To(Equal(expect))
+			}
+		},
+		// GitHub's published webhook signature example
+		Entry("body with hmac-sha256/hex", hmacConfig("${request:body}", "hmac-sha256", "hex", "sha256="),
+			"sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17", nil),
+		Entry("body with hmac-sha256/base64", hmacConfig("${request:body}", "hmac-sha256", "base64", "sha256="),
+			"sha256=dXEH6g6yUJ/CESIczphLijdXC211hsIsRvQ3nIsEPhc=", nil),
+		Entry("body with hmac-sha1/hex", hmacConfig("${request:body}", "hmac-sha1", "hex", "sha1="),
+			"sha1=01dc10d0c83e72ed246219cdd91669667fe2ca59", nil),
+		Entry("timestamp and body", hmacConfig("${request:header=X-Timestamp}.${request:body}", "hmac-sha256", "hex", "sha256="),
+			"sha256=76c83fd0acdf22faed320674fe8e04d528cfe8a17905e720a9611e40677c03b7", nil),
+		Entry("method, path and property", hmacConfig("${request:method} ${request:path} ${prop:tenant}", "hmac-sha512", "hex", "sha512="),
+			"sha512=7370c695680e8482bce1774a6951acd456ec953c7f5b1914e3c50f6b0a615610a6ef643244f0b765489d5ccba364c97f2f04bb269f8a6d88116e73649ae06c24", nil),
+		Entry("invalid algorithm", hmacConfig("${request:body}", "hmac-md5", "hex", "md5="), "", ErrInvalidHMACAlgorithm),
+		Entry("invalid encoding", hmacConfig("${request:body}", "hmac-sha256", "base32", "sha256="), "", ErrInvalidHMACEncoding),
+	)
+
+	It("does nothing without a header", func() {
+		// Arrange
+		cfg := config.NewConfig()
+		req, _ := http.NewRequest("GET", "https://hooks.example.com", nil)
+
+		// Act
+		err := NewHMACSigner(cfg).Sign(req, nil)
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(req.Header).To(BeEmpty())
+	})
+})
@@ -0,0 +1,196 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/keithpaterson/postal/config"
+)
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4DateFormat = "20060102T150405Z"
+	sigV4DayFormat  = "20060102"
+
+	headerAmzDate          = "X-Amz-Date"
+	headerAmzSecurityToken = "X-Amz-Security-Token"
+	headerAmzContentSha256 = "X-Amz-Content-Sha256"
+)
+
+var (
+	ErrMissingCredentials = errors.New("missing aws-sigv4 credentials")
+)
+
+// headers that are routinely modified in transit, so they must not be signed
+var sigV4UnsignedHeaders = []string{"authorization", "user-agent", "expect", "x-amzn-trace-id"}
+
+type sigV4Signer struct {
+	cfg config.AuthConfig
+	now func() time.Time
+}
+
+func newSigV4Signer(cfg config.AuthConfig) *sigV4Signer {
+	return &sigV4Signer{cfg: cfg, now: time.Now}
+}
+
+func (s *sigV4Signer) Sign(req *http.Request, body []byte) error {
+	if s.cfg.AccessKey == "" || s.cfg.SecretKey == "" || s.cfg.Region == "" || s.cfg.Service == "" {
+		return fmt.Errorf("%w: region, service, access-key and secret-key are required", ErrMissingCredentials)
+	}
+
+	now := s.now().UTC()
+	payloadHash := hashHex(body)
+
+	req.Header.Del("Authorization")
+	req.Header.Set(headerAmzDate, now.Format(sigV4DateFormat))
+	if s.cfg.SessionToken != "" {
+		req.Header.Set(headerAmzSecurityToken, s.cfg.SessionToken)
+	}
+	if s.isS3() {
+		req.Header.Set(headerAmzContentSha256, payloadHash)
+	}
+
+	canonicalHeaders, signedHeaders := s.canonicalHeaders(req)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s.canonicalURI(req.URL),
+		s.canonicalQuery(req.URL),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{now.Format(sigV4DayFormat), s.cfg.Region, s.cfg.Service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{sigV4Algorithm, now.Format(sigV4DateFormat), scope, hashHex([]byte(canonicalRequest))}, "\n")
+
+	signature := hex.EncodeToString(hmacSHA256(s.signingKey(now), stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, s.cfg.AccessKey, scope, signedHeaders, signature))
+	return nil
+}
+
+func (s *sigV4Signer) isS3() bool {
+	return s.cfg.Service == "s3"
+}
+
+func (s *sigV4Signer) signingKey(now time.Time) []byte {
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), now.Format(sigV4DayFormat))
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, s.cfg.Service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+// S3 paths are encoded once and are not normalized; every other service normalizes
+// the path and encodes the (already escaped) path a second time.
+func (s *sigV4Signer) canonicalURI(u *url.URL) string {
+	uri := u.EscapedPath()
+	if s.isS3() {
+		uri = u.Path
+	} else if uri != "" {
+		cleaned := path.Clean(uri)
+		if strings.HasSuffix(uri, "/") && cleaned != "/" {
+			cleaned += "/"
+		}
+		uri = cleaned
+	}
+	if uri == "" {
+		return "/"
+	}
+
+	segments := strings.Split(uri, "/")
+	for index, segment := range segments {
+		segments[index] = uriEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func (s *sigV4Signer) canonicalQuery(u *url.URL) string {
+	// sorted by encoded key and then by encoded value; sorting the joined "key=value" strings would put
+	// e.g. "a-b=1" before "a=2"
+	type pair struct{ key, value string }
+	query := u.Query()
+	pairs := make([]pair, 0, len(query))
+	for key, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, pair{uriEncode(key), uriEncode(value)})
+		}
+	}
+	slices.SortFunc(pairs, func(a, b pair) int {
+		if result := strings.Compare(a.key, b.key); result != 0 {
+			return result
+		}
+		return strings.Compare(a.value, b.value)
+	})
+
+	encoded := make([]string, len(pairs))
+	for index, p := range pairs {
+		encoded[index] = p.key + "=" + p.value
+	}
+	return strings.Join(encoded, "&")
+}
+
+func (s *sigV4Signer) canonicalHeaders(req *http.Request) (string, string) {
+	headers := map[string]string{}
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers["host"] = host
+
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if slices.Contains(sigV4UnsignedHeaders, name) {
+			continue
+		}
+		trimmed := make([]string, len(values))
+		for index, value := range values {
+			trimmed[index] = strings.Join(strings.Fields(value), " ")
+		}
+		headers[name] = strings.Join(trimmed, ",")
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var canonical strings.Builder
+	for _, name := range names {
+		canonical.WriteString(name + ":" + headers[name] + "\n")
+	}
+	return canonical.String(), strings.Join(names, ";")
+}
+
+// uriEncode encodes everything except the RFC 3986 unreserved characters
+func uriEncode(value string) string {
+	var result strings.Builder
+	for _, b := range []byte(value) {
+		if ('A' <= b && b <= 'Z') || ('a' <= b && b <= 'z') || ('0' <= b && b <= '9') || b == '-' || b == '_' || b == '.' || b == '~' {
+			result.WriteByte(b)
+		} else {
+			fmt.Fprintf(&result, "%%%02X", b)
+		}
+	}
+	return result.String()
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
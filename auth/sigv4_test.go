@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/keithpaterson/postal/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// credentials and timestamp used by the published AWS SigV4 test suite
+var (
+	testVectorCfg = config.AuthConfig{
+		Type:      "aws-sigv4",
+		Region:    "us-east-1",
+		Service:   "service",
+		AccessKey: "AKIDEXAMPLE",
+		SecretKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}
+	testVectorTime = time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+)
+
+func testVectorRequest(method string, url string, body string, headers ...string) *http.Request {
+	req, err := http.NewRequest(method, url, bytes.NewBufferString(body))
+	Expect(err).ToNot(HaveOccurred())
+	for index := 0; index < len(headers); index += 2 {
+		req.Header.Add(headers[index], headers[index+1])
+	}
+	return req
+}
+
+var _ = Describe("SigV4", func() {
+	DescribeTable("AWS test vectors",
+		func(req *http.Request, body string, expect string) {
+			// Arrange
+			signer := newSigV4Signer(testVectorCfg)
+			signer.now = func() time.Time { return testVectorTime }
+
+			// Act
+			err := signer.Sign(req, []byte(body))
+
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(req.Header.Get("X-Amz-Date")).To(Equal("20150830T123600Z"))
+			Expect(req.Header.Get("Authorization")).To(Equal(expect))
+		},
+		Entry("get-vanilla",
+			testVectorRequest("GET", "https://example.amazonaws.com/", ""), "",
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"),
+		Entry("get-vanilla-query-order-key-case",
+			testVectorRequest("GET", "https://example.amazonaws.com/?Param2=value2&Param1=value1", ""), "",
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500"),
+		Entry("get-vanilla-empty-query-key",
+			testVectorRequest("GET", "https://example.amazonaws.com/?Param1=value1", ""), "",
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=a67d582fa61cc504c4bae71f336f98b97f1ea3c7a6bfe1b6e45aec72011b9aeb"),
+		Entry("get-header-value-trim",
+			testVectorRequest("GET", "https://example.amazonaws.com/", "", "My-Header1", " value1", "My-Header2", ` "a   b   c"`), "",
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;my-header1;my-header2;x-amz-date, Signature=acc3ed3afb60bb290fc8d2dd0098b9911fcaa05412b367055dee359757a9c736"),
+		Entry("post-vanilla",
+			testVectorRequest("POST", "https://example.amazonaws.com/", ""), "",
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b"),
+		Entry("post-x-www-form-urlencoded",
+			testVectorRequest("POST", "https://example.amazonaws.com/", "Param1=value1", "Content-Type", "application/x-www-form-urlencoded"), "Param1=value1",
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=content-type;host;x-amz-date, Signature=ff11897932ad3f4e8b18135d722051e5ac45fc38421b1da7b9d196a0fe09473a"),
+	)
+
+	DescribeTable("canonical query",
+		func(rawURL string, expect string) {
+			// Arrange
+			signer := newSigV4Signer(testVectorCfg)
+			u, err := url.Parse(rawURL)
+			Expect(err).ToNot(HaveOccurred())
+
+			// Act
+			actual := signer.canonicalQuery(u)
+
+			// Assert
+			Expect(actual).To(Equal(expect))
+		},
+		Entry("no query", "https://example.amazonaws.com/", ""),
+		Entry("sorted by key", "https://example.amazonaws.com/?b=1&a=2", "a=2&b=1"),
+		Entry("key that prefixes another key", "https://example.amazonaws.com/?a-b=1&a=2", "a=2&a-b=1"),
+		Entry("same key sorted by value", "https://example.amazonaws.com/?a=2&a=1", "a=1&a=2"),
+		Entry("encoded keys and values", "https://example.amazonaws.com/?b=x%20y&a%2Fb=1", "a%2Fb=1&b=x%20y"),
+	)
+
+	It("adds the session token to the signed headers", func() {
+		// Arrange
+		cfg := testVectorCfg
+		cfg.SessionToken = "session"
+		signer := newSigV4Signer(cfg)
+		req := testVectorRequest("GET", "https://example.amazonaws.com/", "")
+
+		// Act
+		err := signer.Sign(req, nil)
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(req.Header.Get("X-Amz-Security-Token")).To(Equal("session"))
+		Expect(req.Header.Get("Authorization")).To(ContainSubstring("SignedHeaders=host;x-amz-date;x-amz-security-token,"))
+	})
+
+	It("adds the content hash for s3", func() {
+		// Arrange
+		cfg := testVectorCfg
+		cfg.Service = "s3"
+		signer := newSigV4Signer(cfg)
+		req := testVectorRequest("PUT", "http://localhost:9000/bucket/my object.txt", "hello")
+
+		// Act
+		err := signer.Sign(req, []byte("hello"))
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(req.Header.Get("X-Amz-Content-Sha256")).To(Equal("2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"))
+		Expect(req.Header.Get("Authorization")).To(ContainSubstring("SignedHeaders=host;x-amz-content-sha256;x-amz-date,"))
+	})
+
+	It("requires credentials", func() {
+		// Arrange
+		cfg := testVectorCfg
+		cfg.SecretKey = ""
+		signer := newSigV4Signer(cfg)
+
+		// Act
+		err := signer.Sign(testVectorRequest("GET", "https://example.amazonaws.com/", ""), nil)
+
+		// Assert
+		Expect(err).To(MatchError(ErrMissingCredentials))
+	})
+})
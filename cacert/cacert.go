@@ -3,6 +3,7 @@ package cacert
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
 
 	"github.com/keithpaterson/postal/config"
 )
@@ -22,3 +23,20 @@ func (p cacertParser) GetCertificatePool() (*x509.CertPool, error) {
 func (p cacertParser) GetCertificates() ([]tls.Certificate, error) {
 	return Certificates().FromConfig(p.cfg).Build()
 }
+
+// TLSConfig returns the TLS configuration for HTTP clients that use the pool and certificates,
+// or nil when the pool is "none" (i.e. the default configuration should be used).
+func (p cacertParser) TLSConfig() (*tls.Config, error) {
+	if p.cfg.Pool() == config.CertPoolNone {
+		return nil, nil
+	}
+	pool, err := p.GetCertificatePool()
+	if err != nil {
+		return nil, fmt.Errorf("invalid pool: %w", err)
+	}
+	certificates, err := p.GetCertificates()
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificates: %w", err)
+	}
+	return &tls.Config{RootCAs: pool, Certificates: certificates}, nil
+}
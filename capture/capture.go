@@ -0,0 +1,136 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/keithpaterson/postal/config"
+	"github.com/keithpaterson/postal/jsonpath"
+
+	"github.com/BurntSushi/toml"
+)
+
+const (
+	DefaultFile = "captured.toml"
+)
+
+var (
+	ErrCaptureFailed = errors.New("failed to capture value")
+	ErrNotFound      = errors.New("value not found")
+)
+
+// the capture file only contains properties
+type captureFile struct {
+	Properties config.Properties `toml:"properties"`
+}
+
+// Values extracts the captured values from the response; body is the response body,
+// which the caller has already read.
+func Values(captures []config.CaptureConfig, resp *http.Response, body []byte) (config.Properties, error) {
+	props := make(config.Properties)
+	var errs []error
+	for _, capture := range captures {
+		value, err := extract(capture, resp, body)
+		if errors.Is(err, ErrNotFound) && capture.Default != nil {
+			value, err = *capture.Default, nil
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: '%s': %w", ErrCaptureFailed, capture.Name, err))
+			continue
+		}
+		props[capture.Name] = value
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return props, nil
+}
+
+// Save merges props into the [properties] of filename (DefaultFile if it is empty), creating it if necessary.
+func Save(filename string, props config.Properties) error {
+	if filename == "" {
+		filename = DefaultFile
+	}
+
+	file := captureFile{Properties: make(config.Properties)}
+	if _, err := toml.DecodeFile(filename, &file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: failed to read '%s': %w", ErrCaptureFailed, filename, err)
+	}
+	for name, value := range props {
+		file.Properties[name] = value
+	}
+
+	if dir := filepath.Dir(filename); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("%w: %w", ErrCaptureFailed, err)
+		}
+	}
+	// write a temporary file first so that a failure doesn't lose the existing properties
+	temp := filename + ".tmp"
+	out, err := os.Create(temp)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCaptureFailed, err)
+	}
+	err = toml.NewEncoder(out).Encode(file)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(temp, filename)
+	}
+	if err != nil {
+		os.Remove(temp)
+		return fmt.Errorf("%w: failed to write '%s': %w", ErrCaptureFailed, filename, err)
+	}
+	return nil
+}
+
+func extract(capture config.CaptureConfig, resp *http.Response, body []byte) (string, error) {
+	switch capture.From {
+	case "header":
+		values := resp.Header.Values(capture.Expr)
+		if len(values) == 0 {
+			return "", fmt.Errorf("%w: header '%s'", ErrNotFound, capture.Expr)
+		}
+		return strings.Join(values, ","), nil
+	case "status":
+		return strconv.Itoa(resp.StatusCode), nil
+	case "json":
+		return jsonValue(body, capture.Expr)
+	case "regex":
+		return regexValue(body, capture.Expr)
+	}
+	return "", fmt.Errorf("unsupported source '%s'", capture.From)
+}
+
+func jsonValue(body []byte, path string) (string, error) {
+	value, err := jsonpath.Lookup(body, path)
+	if errors.Is(err, jsonpath.ErrPathNotFound) {
+		return "", fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+	if err != nil {
+		return "", err
+	}
+	return jsonpath.String(value)
+}
+
+func regexValue(body []byte, expr string) (string, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return "", err
+	}
+	match := re.FindSubmatch(body)
+	if match == nil {
+		return "", fmt.Errorf("%w: no match for '%s'", ErrNotFound, expr)
+	}
+	if len(match) > 1 {
+		return string(match[1]), nil
+	}
+	return string(match[0]), nil
+}
@@ -0,0 +1,16 @@
+package capture_test
+
+import (
+	"testing"
+
+	"github.com/keithpaterson/postal/logging"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCapture(t *testing.T) {
+	logging.Disable()
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Capture Suite")
+}
@@ -0,0 +1,114 @@
+package capture
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/keithpaterson/postal/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func newCaptureResponse() *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusCreated,
+		Header:     http.Header{"Location": {"/orders/42"}, "X-Tag": {"a", "b"}},
+	}
+}
+
+func withDefault(capture config.CaptureConfig, value string) config.CaptureConfig {
+	capture.Default = &value
+	return capture
+}
+
+var _ = Describe("Capture", func() {
+	body := []byte(`{"data":{"id":"ord-42","items":[{"qty":3}]}} ref=REF-7`)
+
+	DescribeTable("Values",
+		func(capture config.CaptureConfig, expect string, expectErr error) {
+			// Act
+			props, err := Values([]config.CaptureConfig{capture}, newCaptureResponse(), body)
+
+			// Assert
+			if expectErr != nil {
+				Expect(err).To(MatchError(ErrCaptureFailed))
+				Expect(err).To(MatchError(expectErr))
+				Expect(props).To(BeNil())
+			} else {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(props).To(Equal(config.Properties{capture.Name: expect}))
+			}
+		},
+		Entry("header", config.CaptureConfig{Name: "loc", From: "header", Expr: "location"}, "/orders/42", nil),
+		Entry("multiple header values", config.CaptureConfig{Name: "tag", From: "header", Expr: "X-Tag"}, "a,b", nil),
+		Entry("status", config.CaptureConfig{Name: "code", From: "status"}, "201", nil),
+		Entry("regex group", config.CaptureConfig{Name: "qty", From: "regex", Expr: `"qty":(\d+)`}, "3", nil),
+		Entry("regex without a group", config.CaptureConfig{Name: "ref", From: "regex", Expr: `REF-\d+`}, "REF-7", nil),
+		Entry("missing header", config.CaptureConfig{Name: "x", From: "header", Expr: "X-Missing"}, "", ErrNotFound),
+		Entry("no match", config.CaptureConfig{Name: "x", From: "regex", Expr: `nope`}, "", ErrNotFound),
+		Entry("default", withDefault(config.CaptureConfig{Name: "x", From: "header", Expr: "X-Missing"}, "none"), "none", nil),
+	)
+
+	It("captures json values", func() {
+		// Act
+		props, err := Values([]config.CaptureConfig{{Name: "order_id", From: "json", Expr: "data.id"}}, newCaptureResponse(), []byte(`{"data":{"id":"ord-42"}}`))
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(props).To(Equal(config.Properties{"order_id": "ord-42"}))
+	})
+
+	Describe("Save", func() {
+		var dir string
+		BeforeEach(func() {
+			dir = GinkgoT().TempDir()
+		})
+
+		It("creates the file", func() {
+			// Arrange
+			filename := filepath.Join(dir, "out", "captured.toml")
+
+			// Act
+			err := Save(filename, config.Properties{"order_id": "ord-42"})
+
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			cfg := config.NewConfig()
+			file, err := os.Open(filename)
+			Expect(err).ToNot(HaveOccurred())
+			defer file.Close()
+			Expect(cfg.Load(file)).To(Succeed())
+			Expect(cfg.Properties).To(Equal(config.Properties{"order_id": "ord-42"}))
+		})
+
+		It("keeps existing properties", func() {
+			// Arrange
+			filename := filepath.Join(dir, "captured.toml")
+			Expect(os.WriteFile(filename, []byte("[properties]\ntoken = \"abc\"\norder_id = \"old\"\n"), 0644)).To(Succeed())
+
+			// Act
+			err := Save(filename, config.Properties{"order_id": "new"})
+
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			data, _ := os.ReadFile(filename)
+			Expect(string(data)).To(Equal("[properties]\n  order_id = \"new\"\n  token = \"abc\"\n"))
+		})
+
+		It("reports invalid files", func() {
+			// Arrange
+			filename := filepath.Join(dir, "captured.toml")
+			Expect(os.WriteFile(filename, []byte("not toml"), 0644)).To(Succeed())
+
+			// Act
+			err := Save(filename, config.Properties{"order_id": "new"})
+
+			// Assert
+			Expect(err).To(MatchError(ErrCaptureFailed))
+			data, _ := os.ReadFile(filename)
+			Expect(string(data)).To(Equal("not toml"))
+		})
+	})
+})
@@ -0,0 +1,16 @@
+/*
+package capture extracts values from a response into properties, and saves them for later runs.
+
+Each [[capture]] entry names a property and where its value comes from: a response header, the status code,
+a json path or a regular expression applied to the body (see [config.CaptureConfig]).
+The values are saved as a [properties] table in [output] capture-file (default "captured.toml"),
+so a later run can load them along with its own configuration:
+
+	postal send -c create-order.toml     # captures order_id
+	postal send -c get-order.toml -c captured.toml   # uses ${order_id}
+
+Properties that are already in the file are kept unless they are captured again, so several runs
+can contribute to the same file.  The file is only written when every value was captured
+(or has a default); otherwise it is left unchanged and the error describes what could not be found.
+*/
+package capture
@@ -1,2 +1,21 @@
-// package cmd contains cobra struct that handle command-line processing
+/*
+package cmd contains cobra struct that handle command-line processing
+
+Command-line flags override the config files, but only when they are given; e.g. the default for --template
+("${response:body}") does not replace a template set in [output].
+
+'postal send' exits with a code that scripts can branch on:
+  - 0: the request was sent and the response was written
+  - 1: an unexpected error
+  - 2: invalid command-line arguments
+  - 3: invalid configuration, or the request could not be built
+  - 4: the request could not be sent (e.g. connection refused, TLS or timeout errors)
+  - 5: the response could not be written, or a download could not be saved or verified
+  - 6: the response status matched --fail or --fail-on ([output] fail-on)
+  - 7: one or more [[assert]] entries failed; the report is written to stderr
+
+By default any response (including 4xx and 5xx statuses) is a success; use --fail (same as
+--fail-on ">=400") or [output] fail-on to choose which statuses are failures.
+The response is written before postal exits, even when the status is a failure.
+*/
 package cmd
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/keithpaterson/postal/sender"
+	"github.com/keithpaterson/postal/sender/native"
+)
+
+// exit codes returned by postal; these are part of the CLI contract, so don't renumber them
+const (
+	ExitOK        = 0 // the request was sent and the response was written
+	ExitError     = 1 // an error that doesn't fit any of the categories below
+	ExitUsage     = 2 // invalid command-line arguments
+	ExitConfig    = 3 // invalid configuration, or the request could not be built
+	ExitTransport = 4 // the request could not be sent, or no response was received
+	ExitOutput    = 5 // the response could not be written (or a download could not be saved or verified)
+	ExitStatus    = 6 // the response status matched --fail / [output] fail-on
+	ExitAssert    = 7 // one or more [[assert]] entries failed
+)
+
+const exitCodesHelp = `Exit codes:
+  0  the request was sent and the response was written
+  1  an unexpected error
+  2  invalid command-line arguments
+  3  invalid configuration, or the request could not be built
+  4  the request could not be sent (e.g. connection refused, TLS or timeout errors)
+  5  the response could not be written, or a download could not be saved or verified
+  6  the response status matched --fail or --fail-on ([output] fail-on)
+  7  one or more [[assert]] entries failed`
+
+var (
+	ErrSendFailed = errors.New("failed to send request")
+)
+
+// ExitCode returns the process exit code for an error returned by the root command.
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case !errors.Is(err, ErrSendFailed):
+		// cobra failed to parse the command line before the command ran
+		return ExitUsage
+	case errors.Is(err, native.ErrStatusFailed):
+		return ExitStatus
+	case errors.Is(err, native.ErrAssertFailed):
+		return ExitAssert
+	case errors.Is(err, native.ErrRequestFailed):
+		return ExitTransport
+	case errors.Is(err, native.ErrOutputFailed):
+		return ExitOutput
+	case errors.Is(err, ErrInvalidConfigFile), errors.Is(err, sender.ErrInvalidConfig), errors.Is(err, native.ErrInvalidRequest):
+		return ExitConfig
+	case errors.Is(err, ErrInvalidPropertyValue), errors.Is(err, ErrInvalidHeader), errors.Is(err, ErrInvalidJWTClaim), errors.Is(err, sender.ErrInvalidSender):
+		return ExitUsage
+	}
+	return ExitError
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/keithpaterson/postal/sender"
+	"github.com/keithpaterson/postal/sender/native"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func sendFailed(err error) error {
+	return fmt.Errorf("%w: %w", ErrSendFailed, err)
+}
+
+var _ = Describe("ExitCode", func() {
+	DescribeTable("maps errors to exit codes",
+		func(err error, expect int) {
+			// Act
+			code := ExitCode(err)
+
+			// Assert
+			Expect(code).To(Equal(expect))
+		},
+		Entry("no error", nil, ExitOK),
+		Entry("cobra error", errors.New("unknown flag: --nope"), ExitUsage),
+		Entry("unknown error", sendFailed(errors.New("oops")), ExitError),
+		Entry("invalid property", sendFailed(ErrInvalidPropertyValue), ExitUsage),
+		Entry("invalid sender", sendFailed(sender.ErrInvalidSender), ExitUsage),
+		Entry("config file", sendFailed(ErrInvalidConfigFile), ExitConfig),
+		Entry("invalid config", sendFailed(fmt.Errorf("%w: bad", sender.ErrInvalidConfig)), ExitConfig),
+		Entry("invalid request", sendFailed(fmt.Errorf("%w: bad", native.ErrInvalidRequest)), ExitConfig),
+		Entry("transport", sendFailed(fmt.Errorf("%w: refused", native.ErrRequestFailed)), ExitTransport),
+		Entry("output", sendFailed(fmt.Errorf("%w: disk full", native.ErrOutputFailed)), ExitOutput),
+		Entry("status", sendFailed(fmt.Errorf("%w: 500", native.ErrStatusFailed)), ExitStatus),
+		Entry("assert", sendFailed(fmt.Errorf("%w: 1 of 2", native.ErrAssertFailed)), ExitAssert),
+	)
+})
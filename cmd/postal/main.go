@@ -1,6 +1,9 @@
 package main
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/spf13/cobra"
 
 	"github.com/keithpaterson/postal/cmd"
@@ -8,12 +11,13 @@ import (
 )
 
 func main() {
-	defer logging.Teardown()
-
 	rootCmd := setupCli()
 
-	if err := rootCmd.Execute(); err != nil {
-		panic(err)
+	err := rootCmd.Execute()
+	logging.Teardown()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ERROR:", err)
+		os.Exit(cmd.ExitCode(err))
 	}
 }
 
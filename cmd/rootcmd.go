@@ -20,6 +20,8 @@ func NewRootCommand() *cobra.Command {
 		Long: `Postal allows you to compose and send HTTP requests from the command line
   by concatenating configurations, injecting command-line arguments, environment variables,
   and more.`,
+		// errors are reported by main, which also chooses the exit code
+		SilenceErrors: true,
 	}
 
 	cmd.PersistentFlags().BoolP(dryRunFlag, "d", false, "dry run will not perform the operation")
@@ -8,6 +8,7 @@ import (
 
 	"github.com/keithpaterson/postal/config"
 	"github.com/keithpaterson/postal/logging"
+	"github.com/keithpaterson/postal/redact"
 	"github.com/keithpaterson/postal/sender"
 
 	"github.com/spf13/cobra"
@@ -17,17 +18,29 @@ const (
 	algFlag, aFlag      = "alg", "a"
 	bodyFlag, bFlag     = "body", "b"
 	cacertFlag          = "cacert"
+	captureFileFlag     = "capture-file"
+	colorFlag           = "color"
 	configFlag, cFlag   = "config", "c"
+	downloadFlag        = "download"
+	expectedSHA256Flag  = "expected-sha256"
+	failFlag            = "fail"
+	failOnFlag          = "fail-on"
+	forceBinaryFlag     = "force-binary"
+	harFlag             = "har"
 	headerFlag, hFlag   = "header", "H"
 	jwtFlag             = "jwt"
 	methodFlag, mFlag   = "method", "m"
 	outFileFlag, fFlag  = "out-file", "f"
 	outFmtFlag, oFlag   = "out-format", "o"
+	prettyFlag          = "pretty"
 	propFlag, pFlag     = "prop", "p"
+	reportFlag          = "report"
+	showSecretsFlag     = "show-secrets"
 	signingKeyFlag      = "signing-key"
 	templateFlag, tFlag = "template", "t"
 	urlFlag, uFlag      = "url", "u"
 	usingFlag           = "using"
+	verboseFlag, vFlag  = "verbose", "v"
 )
 
 var (
@@ -41,33 +54,51 @@ func NewSendCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "send -c filename [-c filename...] [flags]",
 		Short: "send a message",
-		Run:   sendMessage,
+		Long: `Send a request and write the response.
+
+` + exitCodesHelp,
+		RunE: sendMessage,
 	}
 
 	cmd.Flags().StringP(algFlag, aFlag, config.DefaultAlgorithm, "JWT algorithm")
 	cmd.Flags().StringP(bodyFlag, bFlag, "", "body specification")
 	cmd.Flags().String(cacertFlag, "", "CA certification specification")
+	cmd.Flags().String(captureFileFlag, "", "save [[capture]] values as properties into this file (default \"captured.toml\")")
+	cmd.Flags().String(colorFlag, "auto", fmt.Sprintf("colorize the output, one of [%s]", config.ColorModeNames))
 	cmd.Flags().StringArrayP(configFlag, cFlag, []string{}, "one or more config file names")
+	cmd.Flags().String(downloadFlag, "", "save the response body to a file, resuming a previous partial download")
+	cmd.Flags().String(expectedSHA256Flag, "", "the SHA-256 digest (hex) that a download must match")
+	cmd.Flags().Bool(failFlag, false, "exit with an error when the response status is 400 or above (same as '--fail-on \">=400\"')")
+	cmd.Flags().String(failOnFlag, "", "exit with an error when the response status matches, e.g. \">=400\", \"5xx\" or \"!2xx\"")
+	cmd.Flags().Bool(forceBinaryFlag, false, "allow the binary output format to write to a terminal")
+	cmd.Flags().String(harFlag, "", "also write the exchange to a HAR (HTTP Archive) file")
 	cmd.Flags().StringArrayP(headerFlag, hFlag, []string{}, "one or more HTTP headers (key=value)")
 	cmd.Flags().StringArray(jwtFlag, []string{}, "one or more JWT claims (key=value)")
 	cmd.Flags().StringP(methodFlag, mFlag, "", "HTTP method")
 	cmd.Flags().StringP(outFileFlag, fFlag, "stdout", "specify a filename to write the result into")
 	cmd.Flags().StringP(outFmtFlag, oFlag, "text", fmt.Sprintf("output format, one of [%s]", config.OutFmtNames))
+	cmd.Flags().Bool(prettyFlag, false, "reformat JSON and XML response bodies")
 	cmd.Flags().StringArrayP(propFlag, pFlag, []string{}, "one or more properties (key=value)")
-	cmd.Flags().String(signingKeyFlag, "", "your signing key; used to sign the JWT token")
-	cmd.Flags().StringP(templateFlag, tFlag, "${response:body}", "template for writing text response output")
+	cmd.Flags().String(reportFlag, "", "write a test report for the request and its assertions: junit[:filename] or tap[:filename]")
+	cmd.Flags().Bool(showSecretsFlag, false, "print and log sensitive values (e.g. authorization headers) instead of masking them")
+	cmd.Flags().String(signingKeyFlag, "", "your signing key; used to sign the JWT token (string:, hex:, file:, pemdata:, env:, base64:, cmd:)")
+	cmd.Flags().StringP(templateFlag, tFlag, "${response:body}", "template for writing text response output (inline, file:filename or @name)")
 	cmd.Flags().StringP(urlFlag, uFlag, "", "URL")
 	cmd.Flags().String(usingFlag, sender.NativeSenderName, fmt.Sprintf("Identifies which sender to use: one of [%s]", sender.Names))
+	cmd.Flags().BoolP(verboseFlag, vFlag, false, "show the request and response lines, headers and TLS details for every hop (same as '-o verbose')")
 
 	cmd.MarkFlagRequired(configFlag)
 
 	return cmd
 }
 
-func sendMessage(cmd *cobra.Command, args []string) {
+func sendMessage(cmd *cobra.Command, args []string) error {
+	// the arguments were parsed successfully, so don't show the usage for errors that follow
+	cmd.SilenceUsage = true
 	if err := sendMessageE(cmd, args); err != nil {
-		fmt.Println("ERROR: failed to send request:", err)
+		return fmt.Errorf("%w: %w", ErrSendFailed, err)
 	}
+	return nil
 }
 
 func sendMessageE(cmd *cobra.Command, _ []string) error {
@@ -83,7 +114,7 @@ func sendMessageE(cmd *cobra.Command, _ []string) error {
 	name, _ := cmd.Flags().GetString(usingFlag)
 
 	log.Debug("dryRun:", parser.dryRun)
-	log.Debugf("%#v", *cfg)
+	log.Debugf("%#v", *redact.New(cfg).Config(cfg))
 
 	sender, err := sender.NewNamedSender(name)
 	if err != nil {
@@ -108,6 +139,7 @@ func (p *sendCmdParser) parseConfig(cmd *cobra.Command) (*config.Config, error)
 	var err error
 	p.cfg = config.NewConfig()
 	p.cfg.Runtime.DryRun = p.dryRun
+	p.cfg.Runtime.ShowSecrets, _ = cmd.Flags().GetBool(showSecretsFlag)
 
 	// order is important here:
 	// - config files are lowest-order data sources; bring all of them in first
@@ -129,6 +161,9 @@ func (p *sendCmdParser) parseConfig(cmd *cobra.Command) (*config.Config, error)
 	if err = p.processOutput(); err != nil {
 		return nil, err
 	}
+	if err = p.processDownload(); err != nil {
+		return nil, err
+	}
 	return p.cfg, nil
 }
 
@@ -266,23 +301,115 @@ func (p *sendCmdParser) processJWTClaims() error {
 func (p *sendCmdParser) processOutput() error {
 	var err error
 
-	var template string
-	if template, err = p.cmd.Flags().GetString(templateFlag); err != nil {
-		return p.flagError(templateFlag, err)
+	if p.cmd.Flags().Changed(templateFlag) {
+		var template string
+		if template, err = p.cmd.Flags().GetString(templateFlag); err != nil {
+			return p.flagError(templateFlag, err)
+		}
+		p.cfg.Output.Template = template
+	}
+
+	if p.cmd.Flags().Changed(outFmtFlag) {
+		var outFormat string
+		if outFormat, err = p.cmd.Flags().GetString(outFmtFlag); err != nil {
+			return p.flagError(outFmtFlag, err)
+		}
+		p.cfg.Output.Format = outFormat
+	}
+
+	if p.cmd.Flags().Changed(verboseFlag) {
+		var verbose bool
+		if verbose, err = p.cmd.Flags().GetBool(verboseFlag); err != nil {
+			return p.flagError(verboseFlag, err)
+		}
+		if verbose {
+			p.cfg.Output.Format = config.OutFmtVerbose.String()
+		}
+	}
+
+	if p.cmd.Flags().Changed(prettyFlag) {
+		if p.cfg.Output.Pretty, err = p.cmd.Flags().GetBool(prettyFlag); err != nil {
+			return p.flagError(prettyFlag, err)
+		}
+	}
+
+	if p.cmd.Flags().Changed(colorFlag) {
+		if p.cfg.Output.Color, err = p.cmd.Flags().GetString(colorFlag); err != nil {
+			return p.flagError(colorFlag, err)
+		}
+	}
+
+	if p.cmd.Flags().Changed(forceBinaryFlag) {
+		if p.cfg.Output.ForceBinary, err = p.cmd.Flags().GetBool(forceBinaryFlag); err != nil {
+			return p.flagError(forceBinaryFlag, err)
+		}
+	}
+
+	if p.cmd.Flags().Changed(outFileFlag) {
+		var outFile string
+		if outFile, err = p.cmd.Flags().GetString(outFileFlag); err != nil {
+			return p.flagError(outFileFlag, err)
+		}
+		p.cfg.Output.Filename = outFile
+	}
+
+	if p.cmd.Flags().Changed(failFlag) {
+		var fail bool
+		if fail, err = p.cmd.Flags().GetBool(failFlag); err != nil {
+			return p.flagError(failFlag, err)
+		}
+		if fail {
+			p.cfg.Output.FailOn = ">=400"
+		}
+	}
+
+	if p.cmd.Flags().Changed(failOnFlag) {
+		if p.cfg.Output.FailOn, err = p.cmd.Flags().GetString(failOnFlag); err != nil {
+			return p.flagError(failOnFlag, err)
+		}
+	}
+
+	if p.cmd.Flags().Changed(captureFileFlag) {
+		if p.cfg.Output.CaptureFile, err = p.cmd.Flags().GetString(captureFileFlag); err != nil {
+			return p.flagError(captureFileFlag, err)
+		}
+	}
+
+	if p.cmd.Flags().Changed(reportFlag) {
+		if p.cfg.Output.Report, err = p.cmd.Flags().GetString(reportFlag); err != nil {
+			return p.flagError(reportFlag, err)
+		}
+	}
+
+	if p.cmd.Flags().Changed(harFlag) {
+		var harFile string
+		if harFile, err = p.cmd.Flags().GetString(harFlag); err != nil {
+			return p.flagError(harFlag, err)
+		}
+		if len(p.cfg.Output.Sinks) == 0 {
+			// keep the regular output; an empty sink uses the [output] settings
+			p.cfg.Output.Sinks = append(p.cfg.Output.Sinks, config.SinkConfig{})
+		}
+		p.cfg.Output.Sinks = append(p.cfg.Output.Sinks, config.SinkConfig{Format: config.OutFmtHar.String(), Filename: harFile})
 	}
-	p.cfg.Output.Template = template
 
-	var outFormat string
-	if outFormat, err = p.cmd.Flags().GetString(outFmtFlag); err != nil {
-		return p.flagError(outFmtFlag, err)
+	return nil
+}
+
+func (p *sendCmdParser) processDownload() error {
+	var err error
+
+	if p.cmd.Flags().Changed(downloadFlag) {
+		if p.cfg.Download.Path, err = p.cmd.Flags().GetString(downloadFlag); err != nil {
+			return p.flagError(downloadFlag, err)
+		}
 	}
-	p.cfg.Output.Format = outFormat
 
-	var outFile string
-	if outFile, err = p.cmd.Flags().GetString(outFileFlag); err != nil {
-		return p.flagError(outFileFlag, err)
+	if p.cmd.Flags().Changed(expectedSHA256Flag) {
+		if p.cfg.Download.ExpectedSHA256, err = p.cmd.Flags().GetString(expectedSHA256Flag); err != nil {
+			return p.flagError(expectedSHA256Flag, err)
+		}
 	}
-	p.cfg.Output.Filename = outFile
 
 	return nil
 }
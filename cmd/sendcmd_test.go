@@ -38,6 +38,16 @@ func makeParsedConfig(request *config.RequestConfig, jwt *config.JWTConfig, cace
 	return cfg
 }
 
+func withDownload(cfg *config.Config, download config.DownloadConfig) *config.Config {
+	cfg.Download = download
+	return cfg
+}
+
+func withShowSecrets(cfg *config.Config) *config.Config {
+	cfg.Runtime.ShowSecrets = true
+	return cfg
+}
+
 var _ = Describe("SendCmd", func() {
 	// TODO(keithpaterson): consider inducing flag errors somehow and testing those error paths
 	DescribeTable("parseConfig",
@@ -82,5 +92,24 @@ var _ = Describe("SendCmd", func() {
 		Entry("invalid jwt claim returns error", testData{[]string{"--jwt", "not valid"}, noArgs.cfg}, ErrInvalidJWTClaim),
 		Entry("one jwt claim succeeds", testData{[]string{"--jwt", "foo=bar"}, makeParsedConfig(nil, &config.JWTConfig{Header: config.JWTHeader{Alg: "hs256"}, Claims: config.JWTClaims{"foo": "bar"}}, nil, nil, nil)}, nil),
 		Entry("two jwt claim succeeds", testData{[]string{"--jwt", "foo=bar", "--jwt", "this=that,those"}, makeParsedConfig(nil, &config.JWTConfig{Header: config.JWTHeader{Alg: "hs256"}, Claims: config.JWTClaims{"foo": "bar", "this": "that,those"}}, nil, nil, nil)}, nil),
+		// output tests
+		Entry("output config file is not overridden", testData{[]string{"-c", "testdata/output.cfg"}, makeParsedConfig(nil, nil, nil, nil, &config.OutputConfig{Format: "text", Filename: "stderr", Template: "{{ .Status }}", Engine: "go", TabWidth: 4, Pretty: true, Color: "auto"})}, nil),
+		Entry("config values are kept for output flags that are not given", testData{[]string{"-c", "testdata/output.cfg", "-o", "json"}, makeParsedConfig(nil, nil, nil, nil, &config.OutputConfig{Format: "json", Filename: "stderr", Template: "{{ .Status }}", Engine: "go", TabWidth: 4, Pretty: true, Color: "auto"})}, nil),
+		Entry("output flags override config file", testData{[]string{"-c", "testdata/output.cfg", "-t", "${response:status}", "-f", "stdout", "--pretty=false"}, makeParsedConfig(nil, nil, nil, nil, &config.OutputConfig{Format: "text", Filename: "stdout", Template: "${response:status}", Engine: "go", TabWidth: 4, Color: "auto"})}, nil),
+		Entry("output format is stored", testData{[]string{"-o", "json"}, makeParsedConfig(nil, nil, nil, nil, &config.OutputConfig{Format: "json", Filename: "stdout", Template: "${response:body}", Engine: "resolver", TabWidth: 4, Color: "auto"})}, nil),
+		Entry("verbose selects verbose output", testData{[]string{"-v"}, makeParsedConfig(nil, nil, nil, nil, &config.OutputConfig{Format: "verbose", Filename: "stdout", Template: "${response:body}", Engine: "resolver", TabWidth: 4, Color: "auto"})}, nil),
+		Entry("verbose overrides output format", testData{[]string{"-o", "raw", "--verbose"}, makeParsedConfig(nil, nil, nil, nil, &config.OutputConfig{Format: "verbose", Filename: "stdout", Template: "${response:body}", Engine: "resolver", TabWidth: 4, Color: "auto"})}, nil),
+		Entry("pretty is stored", testData{[]string{"--pretty"}, makeParsedConfig(nil, nil, nil, nil, &config.OutputConfig{Format: "text", Filename: "stdout", Template: "${response:body}", Engine: "resolver", TabWidth: 4, Pretty: true, Color: "auto"})}, nil),
+		Entry("color is stored", testData{[]string{"--color", "never"}, makeParsedConfig(nil, nil, nil, nil, &config.OutputConfig{Format: "text", Filename: "stdout", Template: "${response:body}", Engine: "resolver", TabWidth: 4, Color: "never"})}, nil),
+		Entry("force binary is stored", testData{[]string{"-o", "binary", "--force-binary"}, makeParsedConfig(nil, nil, nil, nil, &config.OutputConfig{Format: "binary", Filename: "stdout", Template: "${response:body}", Engine: "resolver", TabWidth: 4, Color: "auto", ForceBinary: true})}, nil),
+		Entry("har adds a sink", testData{[]string{"--har", "out.har"}, makeParsedConfig(nil, nil, nil, nil, &config.OutputConfig{Format: "text", Filename: "stdout", Template: "${response:body}", Engine: "resolver", TabWidth: 4, Color: "auto", Sinks: []config.SinkConfig{{}, {Format: "har", Filename: "out.har"}}})}, nil),
+		Entry("fail sets fail-on", testData{[]string{"--fail"}, makeParsedConfig(nil, nil, nil, nil, &config.OutputConfig{Format: "text", Filename: "stdout", Template: "${response:body}", Engine: "resolver", TabWidth: 4, Color: "auto", FailOn: ">=400"})}, nil),
+		Entry("fail-on is stored", testData{[]string{"--fail", "--fail-on", "5xx"}, makeParsedConfig(nil, nil, nil, nil, &config.OutputConfig{Format: "text", Filename: "stdout", Template: "${response:body}", Engine: "resolver", TabWidth: 4, Color: "auto", FailOn: "5xx"})}, nil),
+		Entry("capture file is stored", testData{[]string{"--capture-file", "out/ids.toml"}, makeParsedConfig(nil, nil, nil, nil, &config.OutputConfig{Format: "text", Filename: "stdout", Template: "${response:body}", Engine: "resolver", TabWidth: 4, Color: "auto", CaptureFile: "out/ids.toml"})}, nil),
+		Entry("report is stored", testData{[]string{"--report", "junit:results.xml"}, makeParsedConfig(nil, nil, nil, nil, &config.OutputConfig{Format: "text", Filename: "stdout", Template: "${response:body}", Engine: "resolver", TabWidth: 4, Color: "auto", Report: "junit:results.xml"})}, nil),
+		// download tests
+		Entry("download is stored", testData{[]string{"--download", "out/file.zip", "--expected-sha256", "abc"}, withDownload(makeParsedConfig(nil, nil, nil, nil, nil), config.DownloadConfig{Path: "out/file.zip", ExpectedSHA256: "abc"})}, nil),
+		// runtime tests
+		Entry("show secrets is stored", testData{[]string{"--show-secrets"}, withShowSecrets(makeParsedConfig(nil, nil, nil, nil, nil))}, nil),
 	)
 })
@@ -0,0 +1,47 @@
+package config
+
+// AssertConfig checks the response; postal reports every assertion and exits with an error if any fail, e.g.
+//
+//	[[assert]]
+//	check = "status"
+//	expr = "2xx"
+//
+//	[[assert]]
+//	check = "json"
+//	expr = "data.items"
+//	type = "array"
+//
+//	[[assert]]
+//	check = "latency"
+//	max = 500
+type AssertConfig struct {
+	// Name describes the assertion in the report; a description is generated if it is empty.
+	Name string `toml:"name,omitempty"`
+
+	// Check is what is being checked:
+	//  "status": the status code matches Expr, e.g. "200", "2xx" or ">=200,<300"; see the [statusmatch] package
+	//  "header": the response header named by Expr
+	//  "json": the value found at the json path Expr, e.g. "data.items[0].id"; see the [jsonpath] package
+	//  "body": the response body
+	//  "size": the size of the body, in bytes
+	//  "latency": the total time taken, in milliseconds
+	// The json, body and size checks need the body, so they can't be used when it is downloaded ([download] path).
+	Check string `toml:"check"              validate:"required,oneof=status header json body size latency"`
+
+	// Expr is the status specification, header name or json path
+	Expr string `toml:"expr,omitempty"     validate:"required_if=Check status,required_if=Check header,required_if=Check json"`
+
+	// The header, json and body checks compare their value using any of the following; all of them must pass.
+	// Headers and json values must exist unless Exists is false, in which case they must not exist.
+	Equals   *string `toml:"equals,omitempty"`
+	Matches  string  `toml:"matches,omitempty"  validate:"omitempty,gt=0"`
+	Contains string  `toml:"contains,omitempty" validate:"omitempty,gt=0"`
+	Exists   *bool   `toml:"exists,omitempty"`
+
+	// Type is the type of a json value: one of "string", "number", "boolean", "array", "object" or "null"
+	Type string `toml:"type,omitempty"     validate:"omitempty,oneof=string number boolean array object null"`
+
+	// Min and Max are the (inclusive) limits for the size and latency checks
+	Min *float64 `toml:"min,omitempty"`
+	Max *float64 `toml:"max,omitempty"`
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"slices"
+	"strconv"
+)
+
+const (
+	AuthNone AuthType = iota
+	AuthAWSSigV4
+
+	// must be last
+	authMax
+)
+
+const (
+	AuthInvalid = AuthType(-1)
+)
+
+type AuthType int
+
+var authTypeNames = []string{"none", "aws-sigv4"}
+
+func (t AuthType) String() string {
+	if t < 0 || t >= authMax {
+		return strconv.Itoa(int(t))
+	}
+	return authTypeNames[t]
+}
+
+// AuthConfig holds the configuration used to sign the request after its headers and body have been built.
+type AuthConfig struct {
+	// Type identifies the signing scheme; may be "none", in which case this struct is not processed.
+	//  "aws-sigv4" : AWS Signature Version 4 (S3-compatible stores, API Gateway, etc.)
+	// by default, Type is set to "none"
+	Type string `toml:"type,omitempty"          validate:"omitempty,oneof=none aws-sigv4"`
+
+	// Region and Service identify the credential scope, e.g. "us-east-1" and "s3"
+	Region  string `toml:"region,omitempty"        validate:"required_if=Type aws-sigv4"`
+	Service string `toml:"service,omitempty"       validate:"required_if=Type aws-sigv4"`
+
+	// AccessKey, SecretKey and (optionally) SessionToken are your AWS credentials.
+	// It is not recommended that you store these in your configuration; use ${env:...} tokens instead, e.g.
+	//   secret-key = "${env:AWS_SECRET_ACCESS_KEY}"
+	AccessKey    string `toml:"access-key,omitempty"    validate:"required_if=Type aws-sigv4"`
+	SecretKey    string `toml:"secret-key,omitempty"    validate:"required_if=Type aws-sigv4"`
+	SessionToken string `toml:"session-token,omitempty" validate:"omitempty,gt=0"`
+}
+
+func newAuthConfig() AuthConfig {
+	return AuthConfig{Type: AuthNone.String()}
+}
+
+func (c AuthConfig) AuthType() AuthType {
+	index := slices.Index(authTypeNames, c.Type)
+	if index < 0 {
+		return AuthInvalid
+	}
+	return AuthType(index)
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AuthConfig", func() {
+	DescribeTable("AuthType",
+		func(value string, expect AuthType) {
+			// Arrange
+			cfg := AuthConfig{Type: value}
+
+			// Act
+			actual := cfg.AuthType()
+
+			// Assert
+			Expect(actual).To(Equal(expect))
+			if expect != AuthInvalid {
+				Expect(actual.String()).To(Equal(value))
+			}
+		},
+		Entry(nil, "none", AuthNone),
+		Entry(nil, "aws-sigv4", AuthAWSSigV4),
+		Entry(nil, "basic", AuthInvalid),
+		Entry(nil, "", AuthInvalid),
+	)
+})
@@ -0,0 +1,29 @@
+package config
+
+// CaptureConfig extracts a value from the response into a property, which is saved to [output] capture-file
+// so that later runs can use it, e.g.
+//
+//	[[capture]]
+//	name = "order_id"
+//	from = "json"
+//	expr = "data.id"
+//
+// writes order_id to the capture file, and 'postal send -c captured.toml ...' can then use ${order_id}.
+type CaptureConfig struct {
+	// Name is the property that the value is saved as.
+	Name string `toml:"name"              validate:"required,gt=0"`
+
+	// From is where the value comes from:
+	//  "header": the response header named by Expr (multiple values are separated by commas)
+	//  "status": the response status code (Expr is not used)
+	//  "json": the value found at the json path Expr, e.g. "data.items[0].id"; see the [jsonpath] package
+	//  "regex": the first match of the regular expression Expr in the body, or its first group if it has one
+	// json and regex need the body, so they can't be used when it is downloaded ([download] path).
+	From string `toml:"from"              validate:"required,oneof=header status json regex"`
+
+	// Expr selects the value; its meaning depends on From.
+	Expr string `toml:"expr,omitempty"    validate:"required_unless=From status"`
+
+	// Default is saved when the value cannot be found; without a default, a missing value is an error.
+	Default *string `toml:"default,omitempty"`
+}
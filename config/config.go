@@ -47,11 +47,18 @@ var (
 //
 // This will fail validation (improper URL format) and result in an error message
 type Config struct {
-	Request    RequestConfig `toml:"request,omitempty"    validate:"required"`
-	JWT        JWTConfig     `toml:"jwt,omitempty"        validate:"omitempty"`
-	Cacert     CacertConfig  `toml:"cacert,omitempty"     validate:"omitempty"`
-	Properties Properties    `toml:"properties,omitempty" validate:"omitempty,dive,gt=0"`
-	Output     OutputConfig  `toml:"output,omitempty"     validate:"omitempty"`
+	Request    RequestConfig   `toml:"request,omitempty"    validate:"required"`
+	JWT        JWTConfig       `toml:"jwt,omitempty"        validate:"omitempty"`
+	OAuth2     OAuth2Config    `toml:"oauth2,omitempty"     validate:"omitempty"`
+	Auth       AuthConfig      `toml:"auth,omitempty"       validate:"omitempty"`
+	Signing    SigningConfig   `toml:"signing,omitempty"    validate:"omitempty"`
+	Cacert     CacertConfig    `toml:"cacert,omitempty"     validate:"omitempty"`
+	Properties Properties      `toml:"properties,omitempty" validate:"omitempty,dive,gt=0"`
+	Output     OutputConfig    `toml:"output,omitempty"     validate:"omitempty"`
+	Redact     RedactConfig    `toml:"redact,omitempty"     validate:"omitempty"`
+	Download   DownloadConfig  `toml:"download,omitempty"   validate:"omitempty"`
+	Capture    []CaptureConfig `toml:"capture,omitempty"    validate:"omitempty,dive"`
+	Assert     []AssertConfig  `toml:"assert,omitempty"     validate:"omitempty,dive"`
 
 	// never persisted
 	Runtime RuntimeConfig
@@ -63,6 +70,9 @@ func NewConfig() *Config {
 	cfg := &Config{
 		Request:    newRequestConfig(),
 		JWT:        newJWTConfig(),
+		OAuth2:     newOAuth2Config(),
+		Auth:       newAuthConfig(),
+		Signing:    newSigningConfig(),
 		Cacert:     newCacertConfig(),
 		Output:     newOutputConfig(),
 		Properties: make(Properties),
@@ -0,0 +1,16 @@
+package config
+
+// DownloadConfig saves the response body to a file instead of writing output, which suits large artifacts:
+// the body is streamed to disk, interrupted downloads are resumed (if the file hasn't changed), and the result can be verified.
+type DownloadConfig struct {
+	// Path is the file to save the body into; downloads are disabled when it is empty.
+	// The body is written to "<path>.part" and only renamed to Path once it is complete (and verified).
+	// The status is checked against [output] fail-on before anything is written.
+	Path string `toml:"path,omitempty"            validate:"omitempty,gt=0"`
+
+	// ExpectedSHA256 is the hex-encoded SHA-256 digest that the downloaded file must match.
+	ExpectedSHA256 string `toml:"expected-sha256,omitempty" validate:"omitempty,len=64,hexadecimal"`
+
+	// NoProgress disables the progress bar, which is otherwise written to stderr when it is a terminal.
+	NoProgress bool `toml:"no-progress,omitempty"`
+}
@@ -62,9 +62,12 @@ type JWTConfig struct {
 	//  "hex:01 02 03 ..." : hexadecimal representation of your signature (space-separated values), this is your signature key in readable format
 	//  "file:filename"    : locates a file containing your signature in PEM format
 	//  "pemdata:data"     : provides the PEM formatted signature as a text block.
+	//  "env:VAR"          : reads the key from the environment variable VAR
+	//  "base64:data"      : base64 representation of your signature; typically used for raw HMAC secrets
+	//  "cmd:program args" : runs a local helper (such as a password manager CLI) and reads the key from its stdout
 	//
 	// Generally is isn't recommended that you store your signing key in the configuration.
-	// Storing the filename is generally considered safer.
+	// Storing the filename, environment variable name or helper command is generally considered safer.
 	// You can provide the signing key via command-line using one of these format strings.
 	SigningKey string `toml:"signing-key,omitempty" validate:"omitempty,gt=0"`
 }
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"slices"
+	"strconv"
+)
+
+const (
+	GrantClientCredentials GrantType = iota
+	GrantPassword
+	GrantRefreshToken
+
+	// must be last
+	grantMax
+)
+
+const (
+	GrantInvalid = GrantType(-1)
+)
+
+type GrantType int
+
+var grantTypeNames = []string{"client_credentials", "password", "refresh_token"}
+
+func (g GrantType) String() string {
+	if g < 0 || g >= grantMax {
+		return strconv.Itoa(int(g))
+	}
+	return grantTypeNames[g]
+}
+
+// OAuth2Config holds the configuration used to fetch an OAuth2 access token before the request is sent.
+//
+// The token is fetched (at most once per run) when a "${oauth2:access_token}" token is resolved, e.g.
+//
+//	[request.headers]
+//	  authorization = "Bearer ${oauth2:access_token}"
+//
+// Tokens are cached on disk until they expire, so repeated runs do not call the token endpoint.
+type OAuth2Config struct {
+	// TokenURL is the address of the token endpoint.  If TokenURL is empty this struct is not processed.
+	TokenURL string `toml:"token-url,omitempty"     validate:"omitempty,url"`
+
+	// GrantType identifies which grant to request; one of "client_credentials", "password" or "refresh_token".
+	// by default, GrantType is set to "client_credentials"
+	GrantType string `toml:"grant-type,omitempty"    validate:"omitempty,oneof=client_credentials password refresh_token"`
+
+	// ClientID and ClientSecret identify the client application.
+	// It is not recommended that you store the secret in your configuration; use an ${env:...} token instead.
+	ClientID     string `toml:"client-id,omitempty"     validate:"omitempty,gt=0"`
+	ClientSecret string `toml:"client-secret,omitempty" validate:"omitempty,gt=0"`
+
+	// AuthStyle identifies how the client credentials are sent to the token endpoint:
+	//  "basic"  : using HTTP Basic authentication (default)
+	//  "params" : as "client_id" and "client_secret" form parameters
+	AuthStyle string `toml:"auth-style,omitempty"    validate:"omitempty,oneof=basic params"`
+
+	// Scopes and Audience are optional and are sent to the token endpoint if specified.
+	Scopes   []string `toml:"scopes,omitempty"        validate:"omitempty,dive,gt=0"`
+	Audience string   `toml:"audience,omitempty"      validate:"omitempty,gt=0"`
+
+	// Username and Password are required by the "password" grant.
+	Username string `toml:"username,omitempty"      validate:"omitempty,gt=0"`
+	Password string `toml:"password,omitempty"      validate:"omitempty,gt=0"`
+
+	// RefreshToken is required by the "refresh_token" grant.
+	RefreshToken string `toml:"refresh-token,omitempty" validate:"omitempty,gt=0"`
+
+	// CacheDir overrides the folder where tokens are cached.
+	// by default, tokens are cached in the "postal/oauth2" folder under the user's cache directory.
+	CacheDir string `toml:"cache-dir,omitempty"     validate:"omitempty,gt=0"`
+
+	// NoCache disables the on-disk token cache.
+	NoCache bool `toml:"no-cache,omitempty"`
+}
+
+func newOAuth2Config() OAuth2Config {
+	return OAuth2Config{GrantType: GrantClientCredentials.String(), AuthStyle: "basic"}
+}
+
+func (c OAuth2Config) Grant() GrantType {
+	index := slices.Index(grantTypeNames, c.GrantType)
+	if index < 0 {
+		return GrantInvalid
+	}
+	return GrantType(index)
+}
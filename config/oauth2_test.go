@@ -0,0 +1,29 @@
+package config
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OAuth2Config", func() {
+	DescribeTable("Grant",
+		func(value string, expect GrantType) {
+			// Arrange
+			cfg := OAuth2Config{GrantType: value}
+
+			// Act
+			actual := cfg.Grant()
+
+			// Assert
+			Expect(actual).To(Equal(expect))
+			if expect != GrantInvalid {
+				Expect(actual.String()).To(Equal(value))
+			}
+		},
+		Entry(nil, "client_credentials", GrantClientCredentials),
+		Entry(nil, "password", GrantPassword),
+		Entry(nil, "refresh_token", GrantRefreshToken),
+		Entry(nil, "implicit", GrantInvalid),
+		Entry(nil, "", GrantInvalid),
+	)
+})
@@ -5,40 +5,150 @@ import "slices"
 const (
 	OutFmtRaw OutFormat = iota
 	OutFmtText
+	OutFmtJson
+	OutFmtVerbose
+	OutFmtBase64
+	OutFmtHex
+	OutFmtBinary
+	OutFmtHar
 
 	// must be last
 	numOutFormats
 )
 
-// TODO(keithpaterson): could add more output types, e.g. OutBase64, or something like that
+const (
+	ColorAuto ColorMode = iota
+	ColorAlways
+	ColorNever
+
+	// must be last
+	numColorModes
+)
+
+const (
+	EngineResolver TemplateEngine = iota
+	EngineGo
+
+	// must be last
+	numTemplateEngines
+)
 
 var (
-	OutFmtNames = []string{"raw", "text"}
+	EngineNames    = []string{"resolver", "go"}
+	OutFmtNames    = []string{"raw", "text", "json", "verbose", "base64", "hex", "binary", "har"}
+	ColorModeNames = []string{"auto", "always", "never"}
 )
 
 type OutFormat int
 
+type ColorMode int
+
+type TemplateEngine int
+
 // OutputConfig stores the configuration for how to output the response.
 // Templates are described in more detail in the [output] package.
 type OutputConfig struct {
 	// Specify the output format
 	//  "raw": dumps whatever is in the response as-is
 	//  "text": converts the response to text and applies the template before writing it out.
-	Format string `toml:"format,required"     validate:"required,oneof=raw text"`
+	//  "json": writes the request summary, response and timings as a single JSON document.
+	//  "verbose": writes the request and response lines and headers for every hop, TLS details and the body.
+	//  "base64": writes the body base64-encoded (standard alphabet, padded) on a single line.
+	//  "hex": writes the body as a hex dump in the style of 'hexdump -C'.
+	//  "binary": streams the body unchanged; refuses to write to a terminal unless ForceBinary is set.
+	//  "har": writes the requests, responses and timings as an HTTP Archive (HAR 1.2) for browser developer tools.
+	Format string `toml:"format,required"     validate:"required,oneof=raw text json verbose base64 hex binary har"`
 
 	// Specify a file to write the response data into.
 	// The special strings "stdout" and "stderr" can be used to direct output to the system streams.
+	// The filename can contain ${...} tokens (including ${response:...}), which are resolved when the response arrives,
+	// and ${output:counter}, e.g. "out/${prop:env}-${output:counter}.json"; see the [output] package.
 	Filename string `toml:"filename,omitempty"  validate:"omitempty,gt=0"`
 
 	// A text template that can add additional information to the output stream.
 	// If Template is not specified, the default template "${response:Body}" will be used.
+	// The template can also be loaded from a file ("file:templates/summary.tmpl", relative to the current
+	// directory), or selected from Templates by name ("@summary").
 	Template string `toml:"template,omitempty"  validate:"omitempty,gt=0"`
+
+	// Named templates that can be selected using "@name", e.g. [output.templates] summary = "..."
+	// Named templates can also be loaded from files using "file:filename".
+	Templates map[string]string `toml:"templates,omitempty" validate:"omitempty,dive,keys,gt=0,endkeys,gt=0"`
+
+	// TabWidth is the number of spaces that a "\t" escape in an inline template expands to;
+	// 0 writes an actual tab character.
+	TabWidth int `toml:"tab-width"           validate:"gte=0"`
+
+	// NoEscapes disables the processing of "\n" and "\t" escapes in inline templates.
+	NoEscapes bool `toml:"no-escapes,omitempty"`
+
+	// Engine selects how the template is processed:
+	//  "resolver": ${...} tokens are replaced with their values
+	//  "go": the template is a golang text/template (see the [output] package for the data and functions available)
+	Engine string `toml:"engine,omitempty"    validate:"omitempty,oneof=resolver go"`
+
+	// Namespace prefixes that can be used in ${response:xpath=...} expressions, e.g.
+	//  namespaces = { soap = "http://schemas.xmlsoap.org/soap/envelope/" }
+	// When set, xpath matching is namespace-aware; otherwise names match on their local name only.
+	Namespaces map[string]string `toml:"namespaces,omitempty" validate:"omitempty,dive,keys,gt=0,endkeys,gt=0"`
+
+	// Pretty reformats JSON and XML response bodies (based on the Content-Type) for the 'text' and 'verbose' formats.
+	Pretty bool `toml:"pretty,omitempty"`
+
+	// Color controls ANSI colorization of status lines, headers and JSON bodies:
+	//  "auto": only when writing to a terminal (and the NO_COLOR environment variable is not set)
+	//  "always": even when writing to a file or a pipe
+	//  "never": no colorization
+	Color string `toml:"color,omitempty"     validate:"omitempty,oneof=auto always never"`
+
+	// ForceBinary allows the "binary" format to write to a terminal.
+	ForceBinary bool `toml:"force-binary,omitempty"`
+
+	// FailOn makes postal exit with an error (after the output has been written) when the response status matches,
+	// e.g. ">=400", "5xx", "404,5xx" or "!2xx"; see the [statusmatch] package.  'postal send --fail' is the same as ">=400".
+	FailOn string `toml:"fail-on,omitempty"   validate:"omitempty,statusmatch"`
+
+	// CaptureFile is where the [[capture]] values are saved, as a [properties] table that later runs can load
+	// using '-c filename'.  Properties already in the file are kept unless they are captured again.
+	// The default is "captured.toml".
+	CaptureFile string `toml:"capture-file,omitempty" validate:"omitempty,gt=0"`
+
+	// Report writes the outcome of the request and its [[assert]] entries for CI systems: "junit" or "tap",
+	// optionally followed by ":filename" (default "postal-report.xml" or "postal-report.tap"), e.g. "junit:results.xml".
+	// The report is replaced by every run, unless Append is set.  See the [report] package.
+	Report string `toml:"report,omitempty"       validate:"omitempty,report"`
+
+	// Append adds the output to the end of Filename instead of replacing its contents, and adds the request
+	// to an existing Report.  In either case, missing parent directories are created.
+	Append bool `toml:"append,omitempty"`
+
+	// Sinks write the response to several destinations, each with its own format, template and filename, e.g.
+	//  [[output.sinks]]
+	//  format = "raw"
+	//  filename = "resp.bin"
+	//
+	//  [[output.sinks]]
+	//  template = "${response:status}\n"
+	//  filename = "runs.log"
+	//  append = true
+	// When sinks are configured they replace the Filename above; anything a sink does not specify
+	// (including Format, Template and Engine) is taken from the [output] section.
+	Sinks []SinkConfig `toml:"sinks,omitempty" validate:"omitempty,dive"`
+}
+
+// SinkConfig describes one of several destinations for the response.
+type SinkConfig struct {
+	Format   string `toml:"format,omitempty"   validate:"omitempty,oneof=raw text json verbose base64 hex binary har"`
+	Filename string `toml:"filename,omitempty" validate:"omitempty,gt=0"`
+	Template string `toml:"template,omitempty" validate:"omitempty,gt=0"`
+	Engine   string `toml:"engine,omitempty"   validate:"omitempty,oneof=resolver go"`
+	Append   bool   `toml:"append,omitempty"`
 }
 
 type Options map[string]string
 
 func newOutputConfig() OutputConfig {
-	return OutputConfig{Format: "text", Filename: "stdout", Template: "${response:body}"}
+	return OutputConfig{Format: "text", Filename: "stdout", Template: "${response:body}", Engine: "resolver", TabWidth: 4, Color: "auto"}
 }
 
 func (of OutFormat) String() string {
@@ -55,3 +165,53 @@ func (o OutputConfig) OutFormat() OutFormat {
 	}
 	return OutFormat(index)
 }
+
+func (cm ColorMode) String() string {
+	if cm < 0 || cm >= numColorModes {
+		return "undefined"
+	}
+	return ColorModeNames[cm]
+}
+
+func (o OutputConfig) ColorMode() ColorMode {
+	index := slices.Index(ColorModeNames, o.Color)
+	if index < 0 {
+		return ColorAuto
+	}
+	return ColorMode(index)
+}
+
+func (te TemplateEngine) String() string {
+	if te < 0 || te >= numTemplateEngines {
+		return "undefined"
+	}
+	return EngineNames[te]
+}
+
+func (o OutputConfig) TemplateEngine() TemplateEngine {
+	index := slices.Index(EngineNames, o.Engine)
+	if index < 0 {
+		return EngineResolver
+	}
+	return TemplateEngine(index)
+}
+
+// ForSink returns the output configuration for a sink: the sink's own settings, falling back to this one's.
+func (o OutputConfig) ForSink(sink SinkConfig) OutputConfig {
+	result := o
+	result.Sinks = nil
+	result.Append = sink.Append
+	if sink.Format != "" {
+		result.Format = sink.Format
+	}
+	if sink.Filename != "" {
+		result.Filename = sink.Filename
+	}
+	if sink.Template != "" {
+		result.Template = sink.Template
+	}
+	if sink.Engine != "" {
+		result.Engine = sink.Engine
+	}
+	return result
+}
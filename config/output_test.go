@@ -17,6 +17,12 @@ var _ = Describe("OutputConfig", func() {
 			},
 			Entry(nil, OutFmtRaw, "raw"),
 			Entry(nil, OutFmtText, "text"),
+			Entry(nil, OutFmtJson, "json"),
+			Entry(nil, OutFmtVerbose, "verbose"),
+			Entry(nil, OutFmtBase64, "base64"),
+			Entry(nil, OutFmtHex, "hex"),
+			Entry(nil, OutFmtBinary, "binary"),
+			Entry(nil, OutFmtHar, "har"),
 			Entry(nil, OutFormat(-100), "undefined"),
 			Entry(nil, OutFormat(100), "undefined"),
 		)
@@ -35,9 +41,102 @@ var _ = Describe("OutputConfig", func() {
 		},
 		Entry(nil, "raw", OutFmtRaw),
 		Entry(nil, "text", OutFmtText),
+		Entry(nil, "json", OutFmtJson),
+		Entry(nil, "verbose", OutFmtVerbose),
+		Entry(nil, "base64", OutFmtBase64),
+		Entry(nil, "hex", OutFmtHex),
+		Entry(nil, "binary", OutFmtBinary),
+		Entry(nil, "har", OutFmtHar),
 		Entry(nil, "", OutFmtText),
 		Entry(nil, "something", OutFmtText),
 		Entry(nil, "anything", OutFmtText),
 		Entry(nil, "undefined", OutFmtText),
 	)
+
+	Context("ColorMode", func() {
+		DescribeTable("String",
+			func(mode ColorMode, expect string) {
+				// Act
+				actual := mode.String()
+
+				// Assert
+				Expect(actual).To(Equal(expect))
+			},
+			Entry(nil, ColorAuto, "auto"),
+			Entry(nil, ColorAlways, "always"),
+			Entry(nil, ColorNever, "never"),
+			Entry(nil, ColorMode(-1), "undefined"),
+			Entry(nil, ColorMode(100), "undefined"),
+		)
+
+		DescribeTable("ColorMode()",
+			func(value string, expect ColorMode) {
+				// Arrange
+				cfg := OutputConfig{Color: value}
+
+				// Act
+				actual := cfg.ColorMode()
+
+				// Assert
+				Expect(actual).To(Equal(expect))
+			},
+			Entry(nil, "auto", ColorAuto),
+			Entry(nil, "always", ColorAlways),
+			Entry(nil, "never", ColorNever),
+			Entry(nil, "", ColorAuto),
+			Entry(nil, "something", ColorAuto),
+		)
+	})
+
+	Context("TemplateEngine", func() {
+		DescribeTable("String",
+			func(engine TemplateEngine, expect string) {
+				// Act
+				actual := engine.String()
+
+				// Assert
+				Expect(actual).To(Equal(expect))
+			},
+			Entry(nil, EngineResolver, "resolver"),
+			Entry(nil, EngineGo, "go"),
+			Entry(nil, TemplateEngine(-1), "undefined"),
+			Entry(nil, TemplateEngine(100), "undefined"),
+		)
+
+		DescribeTable("TemplateEngine()",
+			func(value string, expect TemplateEngine) {
+				// Arrange
+				cfg := OutputConfig{Engine: value}
+
+				// Act
+				actual := cfg.TemplateEngine()
+
+				// Assert
+				Expect(actual).To(Equal(expect))
+			},
+			Entry(nil, "resolver", EngineResolver),
+			Entry(nil, "go", EngineGo),
+			Entry(nil, "", EngineResolver),
+			Entry(nil, "something", EngineResolver),
+		)
+	})
+
+	DescribeTable("ForSink",
+		func(sink SinkConfig, expect OutputConfig) {
+			// Arrange
+			cfg := newOutputConfig()
+			cfg.Pretty = true
+			cfg.Sinks = []SinkConfig{sink}
+
+			// Act
+			actual := cfg.ForSink(sink)
+
+			// Assert
+			Expect(actual).To(Equal(expect))
+		},
+		Entry("empty sink inherits everything", SinkConfig{},
+			OutputConfig{Format: "text", Filename: "stdout", Template: "${response:body}", Engine: "resolver", TabWidth: 4, Color: "auto", Pretty: true}),
+		Entry("sink settings override", SinkConfig{Format: "raw", Filename: "out/resp.bin", Template: "{{ .Status }}", Engine: "go", Append: true},
+			OutputConfig{Format: "raw", Filename: "out/resp.bin", Template: "{{ .Status }}", Engine: "go", TabWidth: 4, Color: "auto", Pretty: true, Append: true}),
+	)
 })
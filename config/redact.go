@@ -0,0 +1,17 @@
+package config
+
+// RedactConfig identifies additional values that are masked whenever postal prints or logs configuration
+// (e.g. debug logs and dry-run output).
+//
+// Commonly sensitive values (such as the "Authorization" header and the JWT signing key) are always masked;
+// the values configured here are added to that list.  Use the --show-secrets command-line flag to disable masking.
+type RedactConfig struct {
+	// Headers are the (case-insensitive) names of request and response headers to mask.
+	Headers []string `toml:"headers,omitempty"    validate:"omitempty,dive,gt=0"`
+
+	// Properties are the names of properties to mask.
+	Properties []string `toml:"properties,omitempty" validate:"omitempty,dive,gt=0"`
+
+	// Fields identify configuration fields to mask using their configuration file names, e.g. "oauth2.client-id"
+	Fields []string `toml:"fields,omitempty"     validate:"omitempty,dive,gt=0"`
+}
@@ -9,4 +9,7 @@ package config
 type RuntimeConfig struct {
 	// DryRun is true when the program should validate inputs but not perform any actual action.
 	DryRun bool
+
+	// ShowSecrets is true when sensitive values should be printed and logged as-is instead of being masked.
+	ShowSecrets bool
 }
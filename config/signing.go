@@ -0,0 +1,39 @@
+package config
+
+// SigningConfig holds the configuration used to add an HMAC signature header to the request,
+// as required by many webhook-style APIs, e.g.
+//
+//	[signing]
+//	  header = "X-Signature"
+//	  prefix = "sha256="
+//	  template = "${request:header=X-Timestamp}.${request:body}"
+//	  secret = "env:WEBHOOK_SECRET"
+//
+// The signature is computed after the request headers and body are final.
+type SigningConfig struct {
+	// Header is the name of the header that receives the signature.  If Header is empty this struct is not processed.
+	Header string `toml:"header,omitempty"    validate:"omitempty,gt=0"`
+
+	// Template describes the canonical string that is signed.  In addition to the usual tokens, the template
+	// can use "${request:...}" tokens such as ${request:body}, ${request:method}, ${request:path} and
+	// ${request:header=xxx} (refer to the [resolver] package for more details).
+	Template string `toml:"template,omitempty"  validate:"required_with=Header"`
+
+	// Algorithm is one of "hmac-sha1", "hmac-sha256" or "hmac-sha512".
+	// by default, Algorithm is set to "hmac-sha256"
+	Algorithm string `toml:"algorithm,omitempty" validate:"omitempty,oneof=hmac-sha1 hmac-sha256 hmac-sha512"`
+
+	// Encoding is how the signature is written into the header; one of "hex" or "base64".
+	// by default, Encoding is set to "hex"
+	Encoding string `toml:"encoding,omitempty"  validate:"omitempty,oneof=hex base64"`
+
+	// Prefix is prepended to the encoded signature, e.g. "sha256="
+	Prefix string `toml:"prefix,omitempty"    validate:"omitempty"`
+
+	// Secret is the HMAC key.  Accepted formats are the same as JWTConfig.SigningKey, e.g. "env:WEBHOOK_SECRET"
+	Secret string `toml:"secret,omitempty"    validate:"required_with=Header"`
+}
+
+func newSigningConfig() SigningConfig {
+	return SigningConfig{Algorithm: "hmac-sha256", Encoding: "hex"}
+}
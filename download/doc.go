@@ -0,0 +1,17 @@
+/*
+package download saves a response body to a file, for large artifacts that should not be held in memory.
+
+The body is streamed into "<path>.part", which is renamed to the path once the download is complete.
+The version of the file (its ETag, or Last-Modified date) is saved in "<path>.part.validator".
+If a ".part" file already exists (e.g. a previous download was interrupted), the request asks the server to
+resume it using "Range" and "If-Range" headers; servers that support ranges (i.e. that advertise
+"Accept-Ranges: bytes") reply with the rest of the file if it hasn't changed, and otherwise (or for servers
+that don't support ranges) the download simply starts again.  A ".part" file without a saved version is
+always downloaded again in full.
+
+When an expected SHA-256 digest is configured the complete file is verified before it is renamed;
+a file that does not match is removed, so that the next attempt starts from the beginning.
+
+A progress bar is written to stderr when it is a terminal.
+*/
+package download
@@ -0,0 +1,217 @@
+package download
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/keithpaterson/postal/config"
+)
+
+const (
+	partialSuffix   = ".part"
+	validatorSuffix = ".part.validator"
+)
+
+var (
+	ErrDownloadFailed   = errors.New("download failed")
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+)
+
+type Downloader struct {
+	cfg      config.DownloadConfig
+	progress io.Writer
+
+	// the size of the partial file being resumed
+	offset int64
+}
+
+// New creates a downloader; progress is written to stderr when it is a terminal (unless disabled).
+func New(cfg config.DownloadConfig) *Downloader {
+	d := &Downloader{cfg: cfg}
+	if !cfg.NoProgress && isTerminal(os.Stderr) {
+		d.progress = os.Stderr
+	}
+	return d
+}
+
+// WithProgress writes the progress bar to writer (or nowhere, if it is nil)
+func (d *Downloader) WithProgress(writer io.Writer) *Downloader {
+	d.progress = writer
+	return d
+}
+
+// Prepare asks the server to resume the download when a partial file exists.
+//
+// The request only asks for the rest of the file if it is still the same version ("If-Range");
+// partial files without a validator (ETag or Last-Modified) are downloaded again in full.
+func (d *Downloader) Prepare(req *http.Request) {
+	d.offset = 0
+	if req.Method != http.MethodGet {
+		return
+	}
+	info, err := os.Stat(d.partialName())
+	if err != nil || info.Size() == 0 {
+		return
+	}
+	validator, err := os.ReadFile(d.validatorName())
+	if err != nil || len(validator) == 0 {
+		return
+	}
+	d.offset = info.Size()
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-", d.offset))
+	req.Header.Set("If-Range", string(validator))
+}
+
+// Write saves the response body to the configured path.
+func (d *Downloader) Write(resp *http.Response) error {
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	switch {
+	case d.offset > 0 && resp.StatusCode == http.StatusPartialContent:
+		if start, ok := contentRangeStart(resp.Header.Get("Content-Range")); !ok || start != d.offset {
+			return fmt.Errorf("%w: cannot resume at byte %d (Content-Range: '%s')", ErrDownloadFailed, d.offset, resp.Header.Get("Content-Range"))
+		}
+		flags = os.O_WRONLY | os.O_APPEND
+	case d.offset > 0 && resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// nothing left to download if the partial file is already the complete file
+		if size, ok := contentRangeSize(resp.Header.Get("Content-Range")); !ok || size != d.offset {
+			return fmt.Errorf("%w: %s", ErrDownloadFailed, resp.Status)
+		}
+		return d.complete()
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		// the server sent the whole file
+		d.offset = 0
+	default:
+		return fmt.Errorf("%w: %s", ErrDownloadFailed, resp.Status)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(d.cfg.Path), 0o755); err != nil {
+		return err
+	}
+	if d.offset == 0 {
+		if err := d.saveValidator(resp); err != nil {
+			return err
+		}
+	}
+	file, err := os.OpenFile(d.partialName(), flags, 0o666)
+	if err != nil {
+		return err
+	}
+
+	var writer io.Writer = file
+	if d.progress != nil {
+		total := int64(-1)
+		if resp.ContentLength >= 0 {
+			total = d.offset + resp.ContentLength
+		}
+		bar := newProgressBar(d.progress, d.offset, total)
+		defer bar.finish()
+		writer = io.MultiWriter(file, bar)
+	}
+
+	_, err = io.Copy(writer, resp.Body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// keep the partial file so that the download can be resumed
+		return fmt.Errorf("%w: %w", ErrDownloadFailed, err)
+	}
+	return d.complete()
+}
+
+// complete verifies the partial file and moves it to the configured path
+func (d *Downloader) complete() error {
+	os.Remove(d.validatorName())
+	if err := d.verify(); err != nil {
+		os.Remove(d.partialName())
+		return err
+	}
+	return os.Rename(d.partialName(), d.cfg.Path)
+}
+
+// saveValidator records the version of the file being downloaded, so that an interrupted download
+// is only resumed if the file hasn't changed
+func (d *Downloader) saveValidator(resp *http.Response) error {
+	validator := resp.Header.Get("ETag")
+	if validator == "" || strings.HasPrefix(validator, "W/") {
+		// weak ETags can't be used with "If-Range"
+		validator = resp.Header.Get("Last-Modified")
+	}
+	if validator == "" {
+		if err := os.Remove(d.validatorName()); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return os.WriteFile(d.validatorName(), []byte(validator), 0o666)
+}
+
+func (d *Downloader) verify() error {
+	if d.cfg.ExpectedSHA256 == "" {
+		return nil
+	}
+
+	file, err := os.Open(d.partialName())
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	digest := sha256.New()
+	if _, err = io.Copy(digest, file); err != nil {
+		return err
+	}
+	actual := hex.EncodeToString(digest.Sum(nil))
+	if !strings.EqualFold(actual, d.cfg.ExpectedSHA256) {
+		return fmt.Errorf("%w: expected sha256 %s, got %s", ErrChecksumMismatch, strings.ToLower(d.cfg.ExpectedSHA256), actual)
+	}
+	return nil
+}
+
+func (d *Downloader) partialName() string {
+	return d.cfg.Path + partialSuffix
+}
+
+func (d *Downloader) validatorName() string {
+	return d.cfg.Path + validatorSuffix
+}
+
+// contentRangeStart parses the start from "bytes 100-199/200"
+func contentRangeStart(value string) (int64, bool) {
+	spec, ok := strings.CutPrefix(value, "bytes ")
+	if !ok {
+		return 0, false
+	}
+	start, _, ok := strings.Cut(spec, "-")
+	if !ok {
+		return 0, false
+	}
+	result, err := strconv.ParseInt(strings.TrimSpace(start), 10, 64)
+	return result, err == nil
+}
+
+// contentRangeSize parses the size from "bytes */200" (or "bytes 100-199/200")
+func contentRangeSize(value string) (int64, bool) {
+	_, size, ok := strings.Cut(value, "/")
+	if !ok {
+		return 0, false
+	}
+	result, err := strconv.ParseInt(strings.TrimSpace(size), 10, 64)
+	return result, err == nil
+}
+
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
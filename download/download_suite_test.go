@@ -0,0 +1,16 @@
+package download_test
+
+import (
+	"testing"
+
+	"github.com/keithpaterson/postal/logging"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestDownload(t *testing.T) {
+	logging.Disable()
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Download Suite")
+}
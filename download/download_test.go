@@ -0,0 +1,200 @@
+package download
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/keithpaterson/postal/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Downloader", func() {
+	content := []byte(strings.Repeat("0123456789", 100))
+	digest := sha256.Sum256(content)
+	checksum := hex.EncodeToString(digest[:])
+
+	var server *httptest.Server
+	var path string
+	var requests []*http.Request
+	var etag string
+
+	BeforeEach(func() {
+		requests = nil
+		etag = `"v1"`
+		mux := http.NewServeMux()
+		mux.HandleFunc("/file", func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r)
+			w.Header().Set("ETag", etag)
+			// supports ranges, and advertises "Accept-Ranges: bytes"
+			http.ServeContent(w, r, "file", time.Time{}, bytes.NewReader(content))
+		})
+		mux.HandleFunc("/no-ranges", func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r)
+			w.Write(content)
+		})
+		mux.HandleFunc("/missing", http.NotFound)
+		server = httptest.NewServer(mux)
+		path = filepath.Join(GinkgoT().TempDir(), "out", "file.bin")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	download := func(cfg config.DownloadConfig, url string) error {
+		d := New(cfg).WithProgress(nil)
+		req, _ := http.NewRequest("GET", server.URL+url, nil)
+		d.Prepare(req)
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+		return d.Write(resp)
+	}
+
+	It("saves the body", func() {
+		// Act
+		err := download(config.DownloadConfig{Path: path, ExpectedSHA256: checksum}, "/file")
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.ReadFile(path)).To(Equal(content))
+		Expect(path + partialSuffix).ToNot(BeAnExistingFile())
+		Expect(path + validatorSuffix).ToNot(BeAnExistingFile())
+		Expect(requests[0].Header.Get("Range")).To(BeEmpty())
+	})
+
+	It("saves the version of the file while it is downloading", func() {
+		// Arrange
+		d := New(config.DownloadConfig{Path: path}).WithProgress(nil)
+		resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{"Etag": {`"v1"`}}, Body: io.NopCloser(&failingReader{})}
+
+		// Act
+		err := d.Write(resp)
+
+		// Assert
+		Expect(err).To(MatchError(ErrDownloadFailed))
+		Expect(os.ReadFile(path + validatorSuffix)).To(Equal([]byte(`"v1"`)))
+	})
+
+	DescribeTable("resumes a partial download",
+		func(url string, expectRange string) {
+			// Arrange
+			Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+			Expect(os.WriteFile(path+partialSuffix, content[:400], 0o666)).To(Succeed())
+			Expect(os.WriteFile(path+validatorSuffix, []byte(`"v1"`), 0o666)).To(Succeed())
+
+			// Act
+			err := download(config.DownloadConfig{Path: path, ExpectedSHA256: checksum}, url)
+
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(os.ReadFile(path)).To(Equal(content))
+			Expect(requests[0].Header.Get("Range")).To(Equal(expectRange))
+			Expect(requests[0].Header.Get("If-Range")).To(Equal(`"v1"`))
+		},
+		Entry("server supports ranges", "/file", "bytes=400-"),
+		Entry("server ignores ranges", "/no-ranges", "bytes=400-"),
+	)
+
+	It("downloads the whole file when it has changed", func() {
+		// Arrange
+		Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		Expect(os.WriteFile(path+partialSuffix, []byte(strings.Repeat("x", 400)), 0o666)).To(Succeed())
+		Expect(os.WriteFile(path+validatorSuffix, []byte(`"v1"`), 0o666)).To(Succeed())
+		etag = `"v2"`
+
+		// Act
+		err := download(config.DownloadConfig{Path: path, ExpectedSHA256: checksum}, "/file")
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.ReadFile(path)).To(Equal(content))
+		Expect(requests[0].Header.Get("If-Range")).To(Equal(`"v1"`))
+	})
+
+	It("downloads the whole file when the version of the partial file is unknown", func() {
+		// Arrange
+		Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		Expect(os.WriteFile(path+partialSuffix, []byte(strings.Repeat("x", 400)), 0o666)).To(Succeed())
+
+		// Act
+		err := download(config.DownloadConfig{Path: path, ExpectedSHA256: checksum}, "/file")
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.ReadFile(path)).To(Equal(content))
+		Expect(requests[0].Header.Get("Range")).To(BeEmpty())
+	})
+
+	It("completes a partial download that is already complete", func() {
+		// Arrange
+		Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		Expect(os.WriteFile(path+partialSuffix, content, 0o666)).To(Succeed())
+		Expect(os.WriteFile(path+validatorSuffix, []byte(`"v1"`), 0o666)).To(Succeed())
+
+		// Act
+		err := download(config.DownloadConfig{Path: path, ExpectedSHA256: checksum}, "/file")
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.ReadFile(path)).To(Equal(content))
+		Expect(requests[0].Header.Get("Range")).To(Equal("bytes=1000-"))
+		Expect(path + validatorSuffix).ToNot(BeAnExistingFile())
+	})
+
+	It("removes downloads that don't match the checksum", func() {
+		// Act
+		err := download(config.DownloadConfig{Path: path, ExpectedSHA256: strings.Repeat("0", 64)}, "/file")
+
+		// Assert
+		Expect(err).To(MatchError(ErrChecksumMismatch))
+		Expect(err.Error()).To(ContainSubstring(checksum))
+		Expect(path).ToNot(BeAnExistingFile())
+		Expect(path + partialSuffix).ToNot(BeAnExistingFile())
+	})
+
+	It("does not save error responses", func() {
+		// Act
+		err := download(config.DownloadConfig{Path: path}, "/missing")
+
+		// Assert
+		Expect(err).To(MatchError(ErrDownloadFailed))
+		Expect(path).ToNot(BeAnExistingFile())
+		Expect(path + partialSuffix).ToNot(BeAnExistingFile())
+	})
+
+	DescribeTable("progress",
+		func(initial int64, done int64, total int64, expect string) {
+			// Arrange
+			bar := newProgressBar(&bytes.Buffer{}, initial, total)
+			bar.done = done
+
+			// Act
+			line := bar.line(time.Second)
+
+			// Assert
+			Expect(line).To(Equal(expect))
+		},
+		Entry("started", int64(0), int64(0), int64(2048), "[                              ]   0% 0 B / 2.0 KiB 0 B/s"),
+		Entry("half way", int64(0), int64(1024), int64(2048), "[==============>               ]  50% 1.0 KiB / 2.0 KiB 1.0 KiB/s"),
+		Entry("resumed", int64(1024), int64(2048), int64(2048), "[==============================] 100% 2.0 KiB / 2.0 KiB 1.0 KiB/s"),
+		Entry("unknown size", int64(0), int64(3*1024*1024), int64(-1), "3.0 MiB 3.0 MiB/s"),
+	)
+})
+
+type failingReader struct{}
+
+func (r *failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
@@ -0,0 +1,82 @@
+package download
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	progressWidth    = 30
+	progressInterval = 100 * time.Millisecond
+)
+
+// progressBar draws the download progress on a single (terminal) line
+type progressBar struct {
+	writer io.Writer
+	// bytes already downloaded before this transfer (i.e. when resuming)
+	initial int64
+	// -1 when the size is unknown
+	total int64
+	done  int64
+
+	start time.Time
+	drawn time.Time
+}
+
+func newProgressBar(writer io.Writer, initial int64, total int64) *progressBar {
+	return &progressBar{writer: writer, initial: initial, total: total, done: initial, start: time.Now()}
+}
+
+func (p *progressBar) Write(data []byte) (int, error) {
+	p.done += int64(len(data))
+	if now := time.Now(); now.Sub(p.drawn) >= progressInterval {
+		p.drawn = now
+		p.draw(now)
+	}
+	return len(data), nil
+}
+
+func (p *progressBar) finish() {
+	p.draw(time.Now())
+	fmt.Fprintln(p.writer)
+}
+
+func (p *progressBar) draw(now time.Time) {
+	fmt.Fprint(p.writer, "\r", p.line(now.Sub(p.start)))
+}
+
+func (p *progressBar) line(elapsed time.Duration) string {
+	rate := ""
+	if elapsed > 0 {
+		rate = formatBytes(int64(float64(p.done-p.initial)/elapsed.Seconds())) + "/s"
+	}
+
+	if p.total <= 0 {
+		return fmt.Sprintf("%s %s", formatBytes(p.done), rate)
+	}
+
+	fraction := min(float64(p.done)/float64(p.total), 1)
+	filled := int(fraction * progressWidth)
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressWidth-filled)
+	if filled > 0 && filled < progressWidth {
+		bar = bar[:filled-1] + ">" + bar[filled:]
+	}
+	return fmt.Sprintf("[%s] %3d%% %s / %s %s", bar, int(fraction*100), formatBytes(p.done), formatBytes(p.total), rate)
+}
+
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value := float64(size) / unit
+	for _, suffix := range []string{"KiB", "MiB", "GiB"} {
+		if value < unit {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+		value /= unit
+	}
+	return fmt.Sprintf("%.1f TiB", value)
+}
@@ -0,0 +1,23 @@
+/*
+package jsonpath extracts values from JSON documents using simple path expressions.
+
+A path is a sequence of steps separated by '.':
+  - name: the value of the named object member, e.g. "data.items"
+  - [n]: the n'th array element; negative values count back from the end, e.g. "items[0]", "items[-1]"
+  - ["name"] or ['name']: an object member whose name contains special characters, e.g. `headers["Content-Type"]`
+  - * or [*]: every array element or object member (in key order); the result is an array
+  - length(): the number of array elements, object members or string characters
+
+A leading "$" (or "$.") is accepted and ignored, so "$.data.id" and "data.id" are identical.
+An empty path (or "$") selects the whole document.
+
+Examples:
+
+	data.items[0].id     -> the id of the first item
+	data.items[*].id     -> an array containing the id of every item
+	data.items.length()  -> the number of items
+
+Steps that do not match the document produce an [ErrPathNotFound] error that describes
+which part of the path failed; steps following a wildcard silently skip elements that do not match.
+*/
+package jsonpath
@@ -0,0 +1,279 @@
+package jsonpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidPath  = errors.New("invalid json path")
+	ErrPathNotFound = errors.New("json path not found")
+	ErrInvalidJSON  = errors.New("invalid json")
+)
+
+type stepKind int
+
+const (
+	stepKey stepKind = iota
+	stepIndex
+	stepWildcard
+	stepLength
+)
+
+type step struct {
+	kind  stepKind
+	key   string
+	index int
+	// the original text of the path up to and including this step, for error messages
+	text string
+}
+
+// Path is a parsed path expression.
+type Path struct {
+	text  string
+	steps []step
+}
+
+// Parse parses the path expression.
+func Parse(path string) (*Path, error) {
+	p := &Path{text: path}
+	input := strings.TrimSpace(path)
+	input = strings.TrimPrefix(input, "$")
+
+	pos := 0
+	for pos < len(input) {
+		var s step
+		var err error
+		switch input[pos] {
+		case '.':
+			pos++
+			if pos >= len(input) || input[pos] == '.' || input[pos] == '[' {
+				return nil, fmt.Errorf("%w '%s': expected a name at offset %d", ErrInvalidPath, path, pos)
+			}
+			continue
+		case '[':
+			if s, pos, err = parseBracket(input, pos); err != nil {
+				return nil, fmt.Errorf("%w '%s': %w", ErrInvalidPath, path, err)
+			}
+		default:
+			end := strings.IndexAny(input[pos:], ".[")
+			if end < 0 {
+				end = len(input) - pos
+			}
+			s = nameStep(input[pos : pos+end])
+			pos += end
+		}
+		s.text = strings.TrimPrefix(input[:pos], ".")
+		p.steps = append(p.steps, s)
+	}
+	return p, nil
+}
+
+func nameStep(name string) step {
+	switch name {
+	case "*":
+		return step{kind: stepWildcard}
+	case "length()":
+		return step{kind: stepLength}
+	default:
+		return step{kind: stepKey, key: name}
+	}
+}
+
+func parseBracket(input string, pos int) (step, int, error) {
+	end := strings.IndexByte(input[pos:], ']')
+	if end < 0 {
+		return step{}, pos, fmt.Errorf("missing ']' at offset %d", pos)
+	}
+	content := strings.TrimSpace(input[pos+1 : pos+end])
+	next := pos + end + 1
+
+	switch {
+	case content == "*":
+		return step{kind: stepWildcard}, next, nil
+	case len(content) >= 2 && (content[0] == '"' || content[0] == '\'') && content[len(content)-1] == content[0]:
+		return step{kind: stepKey, key: content[1 : len(content)-1]}, next, nil
+	}
+
+	index, err := strconv.Atoi(content)
+	if err != nil {
+		return step{}, pos, fmt.Errorf("invalid index '%s' at offset %d", content, pos)
+	}
+	return step{kind: stepIndex, index: index}, next, nil
+}
+
+// String returns the original path expression.
+func (p *Path) String() string {
+	return p.text
+}
+
+// Get applies the path to a document that was decoded using [Decode].
+func (p *Path) Get(doc any) (any, error) {
+	value, err := p.eval(doc, p.steps)
+	if list, ok := value.(multi); ok {
+		return []any(list), err
+	}
+	return value, err
+}
+
+// multi holds the results of a wildcard step, which are flattened into any enclosing wildcard results
+type multi []any
+
+func (p *Path) eval(value any, steps []step) (any, error) {
+	for index, s := range steps {
+		switch s.kind {
+		case stepKey:
+			object, ok := value.(map[string]any)
+			if !ok {
+				return nil, p.notFound(s, "%s is not an object", typeName(value))
+			}
+			if value, ok = object[s.key]; !ok {
+				return nil, p.notFound(s, "no member named '%s'", s.key)
+			}
+		case stepIndex:
+			array, ok := value.([]any)
+			if !ok {
+				return nil, p.notFound(s, "%s is not an array", typeName(value))
+			}
+			position := s.index
+			if position < 0 {
+				position += len(array)
+			}
+			if position < 0 || position >= len(array) {
+				return nil, p.notFound(s, "index %d out of range (length %d)", s.index, len(array))
+			}
+			value = array[position]
+		case stepLength:
+			switch v := value.(type) {
+			case []any:
+				value = json.Number(strconv.Itoa(len(v)))
+			case map[string]any:
+				value = json.Number(strconv.Itoa(len(v)))
+			case string:
+				value = json.Number(strconv.Itoa(utf8.RuneCountInString(v)))
+			default:
+				return nil, p.notFound(s, "%s has no length", typeName(value))
+			}
+		case stepWildcard:
+			children, ok := elements(value)
+			if !ok {
+				return nil, p.notFound(s, "%s is not an array or object", typeName(value))
+			}
+			results := multi{}
+			for _, child := range children {
+				result, err := p.eval(child, steps[index+1:])
+				if errors.Is(err, ErrPathNotFound) {
+					continue
+				} else if err != nil {
+					return nil, err
+				}
+				if list, ok := result.(multi); ok {
+					results = append(results, list...)
+				} else {
+					results = append(results, result)
+				}
+			}
+			return results, nil
+		}
+	}
+	return value, nil
+}
+
+func (p *Path) notFound(s step, format string, args ...any) error {
+	return fmt.Errorf("%w: '%s' at '%s': %s", ErrPathNotFound, p.text, s.text, fmt.Sprintf(format, args...))
+}
+
+func elements(value any) ([]any, bool) {
+	switch v := value.(type) {
+	case []any:
+		return v, true
+	case map[string]any:
+		result := make([]any, 0, len(v))
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			result = append(result, v[key])
+		}
+		return result, true
+	default:
+		return nil, false
+	}
+}
+
+func typeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number, float64:
+		return "number"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// Decode decodes a JSON document, preserving numbers exactly as they appear.
+func Decode(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidJSON, err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("%w: unexpected data after the document", ErrInvalidJSON)
+	}
+	return doc, nil
+}
+
+// Lookup decodes the document and applies the path to it.
+func Lookup(data []byte, path string) (any, error) {
+	p, err := Parse(path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	return p.Get(doc)
+}
+
+// Raw returns the value as compact JSON.
+func Raw(value any) (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// String returns scalar values as plain strings (without quotes); arrays and objects are returned as compact JSON.
+func String(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case nil:
+		return "null", nil
+	default:
+		return Raw(value)
+	}
+}
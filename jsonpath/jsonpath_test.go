@@ -0,0 +1,87 @@
+package jsonpath
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const testDoc = `{
+	"id": "abc",
+	"count": 12345678901234567890,
+	"ok": true,
+	"none": null,
+	"data": {
+		"items": [
+			{"id": 1, "name": "one", "tags": ["a", "b"]},
+			{"id": 2, "name": "two", "tags": []},
+			{"id": 3}
+		],
+		"odd.key": "dotted"
+	}
+}`
+
+var _ = Describe("JSON Path", func() {
+	DescribeTable("Lookup as string",
+		func(path string, expect string) {
+			// Act
+			value, err := Lookup([]byte(testDoc), path)
+
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			actual, err := String(value)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actual).To(Equal(expect))
+		},
+		Entry("string", "id", "abc"),
+		Entry("leading $", "$.id", "abc"),
+		Entry("big number", "count", "12345678901234567890"),
+		Entry("boolean", "ok", "true"),
+		Entry("null", "none", "null"),
+		Entry("nested", "data.items[0].name", "one"),
+		Entry("negative index", "data.items[-1].id", "3"),
+		Entry("quoted key", `data["odd.key"]`, "dotted"),
+		Entry("single quoted key", `data['odd.key']`, "dotted"),
+		Entry("object", "data.items[1]", `{"id":2,"name":"two","tags":[]}`),
+		Entry("wildcard", "data.items[*].id", "[1,2,3]"),
+		Entry("wildcard skips missing", "data.items.*.name", `["one","two"]`),
+		Entry("nested wildcards flatten", "data.items[*].tags[*]", `["a","b"]`),
+		Entry("array length", "data.items.length()", "3"),
+		Entry("string length", "data.items[0].name.length()", "3"),
+		Entry("wildcard length", "data.items[*].tags.length()", "[2,0]"),
+		Entry("object wildcard", "data.items[0].*", `[1,"one",["a","b"]]`),
+	)
+
+	It("formats raw values", func() {
+		// Arrange
+		value, err := Lookup([]byte(testDoc), "data.items[0].name")
+		Expect(err).ToNot(HaveOccurred())
+
+		// Act
+		actual, err := Raw(value)
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actual).To(Equal(`"one"`))
+	})
+
+	DescribeTable("Lookup errors",
+		func(data string, path string, expect error, message string) {
+			// Act
+			_, err := Lookup([]byte(data), path)
+
+			// Assert
+			Expect(err).To(MatchError(expect))
+			Expect(err.Error()).To(ContainSubstring(message))
+		},
+		Entry("missing member", testDoc, "data.missing.id", ErrPathNotFound, "'data.missing.id' at 'data.missing': no member named 'missing'"),
+		Entry("index out of range", testDoc, "data.items[5]", ErrPathNotFound, "index 5 out of range (length 3)"),
+		Entry("not an object", testDoc, "id.name", ErrPathNotFound, "string is not an object"),
+		Entry("not an array", testDoc, "data[0]", ErrPathNotFound, "object is not an array"),
+		Entry("no length", testDoc, "ok.length()", ErrPathNotFound, "boolean has no length"),
+		Entry("invalid index", testDoc, "data.items[x]", ErrInvalidPath, "invalid index 'x'"),
+		Entry("missing bracket", testDoc, "data.items[0", ErrInvalidPath, "missing ']'"),
+		Entry("empty name", testDoc, "data..items", ErrInvalidPath, "expected a name"),
+		Entry("invalid json", `{"a":`, "a", ErrInvalidJSON, "invalid json"),
+		Entry("trailing data", `{"a":1} {}`, "a", ErrInvalidJSON, "unexpected data"),
+	)
+})
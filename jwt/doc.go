@@ -22,6 +22,12 @@
 //	                e.g. "hex:01 02 03 04 05" -> "[0x01, 0x02, 0x03, 0x04, 0x05]"
 //		   "pemfile" : the value is a PEM filename
 //		   "pemdata" : the value is a PEM signature (e.g. the value you would find in the PEM file)
+//		   "env"     : the value is the name of an environment variable containing the key
+//		   "base64"  : the value is the base64-encoded key (standard or URL alphabet, padding optional)
+//		   "cmd"     : the value is a command line; the command is run and its stdout is used as the key,
+//	                e.g. "cmd:pass show api/signing-key"
+//
+// Key data is loaded once and cached for the remainder of the run; key values are never logged.
 //
 // Assuming no errors, 'token' will contain the complete, signed JWT token.
 package jwt
@@ -36,6 +36,12 @@ func (b *jwtBuilder) MakeToken(cfg config.JWTConfig) (string, error) {
 	return b.signToken(token)
 }
 
+// KeyData returns the raw key data identified by 'spec', which uses the same "type:value" formats as
+// the JWT signing key.  This allows other signing schemes to share the JWT key sources.
+func KeyData(spec string) ([]byte, error) {
+	return NewBuilder().getKeyData(spec)
+}
+
 func (b *jwtBuilder) signToken(token *jwt.Token) (string, error) {
 	var err error
 	var key any
@@ -5,6 +5,7 @@ import (
 
 	"github.com/keithpaterson/postal/config"
 
+	"github.com/keithpaterson/go-tools/env"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -46,6 +47,7 @@ var _ = Describe("JWT", func() {
 
 	BeforeEach(func() {
 		builder = NewBuilder()
+		keys.reset()
 	})
 
 	DescribeTable("MakeToken",
@@ -72,6 +74,62 @@ var _ = Describe("JWT", func() {
 		Entry("with claims generates token", withClaimsJWT2, expectation{withClaimsToken2, nil}),
 	)
 
+	Context("Key Sources", func() {
+		var (
+			origEnv env.Setup
+		)
+		BeforeEach(func() {
+			origEnv = env.New().Set("POSTAL_TEST_KEY", "value without prefix").Unset("POSTAL_TEST_MISSING").Apply()
+		})
+		AfterEach(func() {
+			origEnv.Apply()
+		})
+
+		withKey := func(key string) config.JWTConfig {
+			cfg := withClaimsJWT2
+			cfg.SigningKey = key
+			return cfg
+		}
+
+		DescribeTable("MakeToken",
+			func(jwtCfg config.JWTConfig, expect expectation) {
+				// Act
+				actual, err := builder.MakeToken(jwtCfg)
+
+				// Assert
+				if expect.err != nil {
+					Expect(err).To(MatchError(expect.err))
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(actual).To(Equal(expect.jwtString))
+				}
+			},
+			Entry("env key generates token", withKey("env:POSTAL_TEST_KEY"), expectation{withClaimsToken2, nil}),
+			Entry("missing env key returns error", withKey("env:POSTAL_TEST_MISSING"), expectation{"", ErrNoSigningKey}),
+			Entry("base64 key generates token", withKey("base64:dmFsdWUgd2l0aG91dCBwcmVmaXg="), expectation{withClaimsToken2, nil}),
+			Entry("unpadded base64 key generates token", withKey("base64:dmFsdWUgd2l0aG91dCBwcmVmaXg"), expectation{withClaimsToken2, nil}),
+			Entry("invalid base64 key returns error", withKey("base64:not*base64"), expectation{"", ErrInvalidSigningValue}),
+			Entry("cmd key generates token", withKey("cmd:echo value without prefix"), expectation{withClaimsToken2, nil}),
+			Entry("failing cmd returns error", withKey("cmd:false"), expectation{"", ErrKeySourceFailed}),
+			Entry("cmd without output returns error", withKey("cmd:true"), expectation{"", ErrNoSigningKey}),
+		)
+
+		It("caches the key for the run", func() {
+			// Arrange
+			cfg := withKey("env:POSTAL_TEST_KEY")
+			_, err := builder.MakeToken(cfg)
+			Expect(err).ToNot(HaveOccurred())
+			env.New().Set("POSTAL_TEST_KEY", "a different key").Apply()
+
+			// Act
+			actual, err := builder.MakeToken(cfg)
+
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actual).To(Equal(withClaimsToken2))
+		})
+	})
+
 	Context("Algorithms", func() {
 		DescribeTable("HMAC",
 			func(alg config.JWTAlgorithm, keyval string) {
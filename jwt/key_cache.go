@@ -0,0 +1,37 @@
+package jwt
+
+import "sync"
+
+// keys caches the raw signing key data for the duration of the run, so that a key source
+// such as "cmd:" is only invoked once no matter how many tokens are generated.
+var keys = &keyCache{data: make(map[string][]byte)}
+
+type keyCache struct {
+	mutex sync.Mutex
+	data  map[string][]byte
+}
+
+type keyLoader func(keyType string, value string) ([]byte, error)
+
+func (c *keyCache) get(keyType string, value string, load keyLoader) ([]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	id := keyType + ":" + value
+	if data, ok := c.data[id]; ok {
+		return data, nil
+	}
+
+	data, err := load(keyType, value)
+	if err != nil {
+		return nil, err
+	}
+	c.data[id] = data
+	return data, nil
+}
+
+func (c *keyCache) reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	clear(c.data)
+}
@@ -1,9 +1,11 @@
 package jwt
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"strconv"
 	"strings"
 
@@ -19,14 +21,23 @@ var (
 	ErrInvalidSigningMethod = errors.New("invalid signing method")
 	ErrInvalidPemData       = errors.New("invalid PEM data")
 	ErrParsePEMFailed       = errors.New("failed to parse PEM data")
+	ErrKeySourceFailed      = errors.New("failed to read signing key")
 )
 
 func (b *jwtBuilder) getSigningKey() (any, error) {
-	if b.jwt.SigningKey == "" {
+	rawData, err := b.getKeyData(b.jwt.SigningKey)
+	if err != nil {
+		return nil, err
+	}
+	return b.decodePemData(rawData)
+}
+
+func (b *jwtBuilder) getKeyData(spec string) ([]byte, error) {
+	if spec == "" {
 		return nil, ErrNoSigningKey
 	}
 
-	keyType, value, ok := strings.Cut(b.jwt.SigningKey, ":")
+	keyType, value, ok := strings.Cut(spec, ":")
 	if !ok {
 		value = keyType
 		keyType = "string"
@@ -36,7 +47,7 @@ func (b *jwtBuilder) getSigningKey() (any, error) {
 	if value == "" {
 		return nil, ErrNoSigningKey
 	}
-	return b.parseSigningKey(keyType, value)
+	return keys.get(keyType, value, b.loadKeyData)
 }
 
 func (b *jwtBuilder) getSigningMethod() (jwt.SigningMethod, error) {
@@ -73,7 +84,7 @@ func (b *jwtBuilder) getSigningMethod() (jwt.SigningMethod, error) {
 	}
 }
 
-func (b *jwtBuilder) parseSigningKey(keyType string, value string) (any, error) {
+func (b *jwtBuilder) loadKeyData(keyType string, value string) ([]byte, error) {
 	var err error
 	var rawData []byte
 	switch keyType {
@@ -85,6 +96,12 @@ func (b *jwtBuilder) parseSigningKey(keyType string, value string) (any, error)
 		rawData, err = b.fromFile(value)
 	case "pemdata":
 		rawData = []byte(value)
+	case "env":
+		rawData, err = b.fromEnv(value)
+	case "base64":
+		rawData, err = b.fromBase64(value)
+	case "cmd":
+		rawData, err = b.fromCommand(value)
 	default:
 		return nil, fmt.Errorf("%w '%s'", ErrInvalidKeyType, keyType)
 	}
@@ -92,7 +109,7 @@ func (b *jwtBuilder) parseSigningKey(keyType string, value string) (any, error)
 	if err != nil {
 		return nil, err
 	}
-	return b.decodePemData(rawData)
+	return rawData, nil
 }
 
 func (b *jwtBuilder) fromString(input string) ([]byte, error) {
@@ -122,6 +139,49 @@ func (b *jwtBuilder) fromFile(filename string) ([]byte, error) {
 	return raw, nil
 }
 
+// the key value itself is never included in errors; it could end up in the logs.
+func (b *jwtBuilder) fromEnv(name string) ([]byte, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return nil, fmt.Errorf("%w: environment variable '%s' is not set", ErrNoSigningKey, name)
+	}
+	return []byte(value), nil
+}
+
+func (b *jwtBuilder) fromBase64(input string) ([]byte, error) {
+	// accept both the standard and URL-safe alphabets, with or without padding
+	input = strings.TrimRight(input, "=")
+	raw, err := base64.RawStdEncoding.DecodeString(input)
+	if err != nil {
+		if raw, err = base64.RawURLEncoding.DecodeString(input); err != nil {
+			return nil, fmt.Errorf("%w: invalid base64 data", ErrInvalidSigningValue)
+		}
+	}
+	return raw, nil
+}
+
+// fromCommand runs a local helper (e.g. a password manager CLI) and uses whatever it writes to stdout as the key.
+// A single trailing newline is removed, since most helpers terminate their output with one.
+func (b *jwtBuilder) fromCommand(commandLine string) ([]byte, error) {
+	args := strings.Fields(commandLine)
+	if len(args) == 0 {
+		return nil, ErrNoSigningKey
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: command '%s': %w", ErrKeySourceFailed, args[0], err)
+	}
+
+	out = []byte(strings.TrimSuffix(strings.TrimSuffix(string(out), "\n"), "\r"))
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%w: command '%s' produced no output", ErrNoSigningKey, args[0])
+	}
+	return out, nil
+}
+
 func (b *jwtBuilder) decodePemData(data []byte) (any, error) {
 	switch b.jwt.Header.Algorithm() {
 	case config.AlgHS256, config.AlgHS384, config.AlgHS512:
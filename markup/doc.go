@@ -0,0 +1,14 @@
+/*
+package markup extracts values from XML and HTML documents.
+
+  - [XPath] evaluates an XPath 1.0 expression against an XML (or HTML) document.
+  - [CSS] finds the first element in an HTML document that matches a CSS selector.
+
+XPath expressions are namespace-aware only when namespace prefixes are provided; otherwise element
+and attribute names match on their local name, so "//Envelope/Body/Result" finds the result in a SOAP
+response without having to know which prefixes the server used.
+
+Both return the value as a string; node sets use the value of the first matching node,
+in the same way that the XPath string() function does.
+*/
+package markup
@@ -0,0 +1,118 @@
+package markup
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/antchfx/htmlquery"
+	"github.com/antchfx/xmlquery"
+	"github.com/antchfx/xpath"
+	"golang.org/x/net/html"
+)
+
+var (
+	ErrInvalidExpression = errors.New("invalid expression")
+	ErrInvalidDocument   = errors.New("invalid document")
+	ErrNoMatch           = errors.New("no match")
+)
+
+// XPath evaluates the expression against the document and returns the result as a string.
+//
+// Set isHTML to parse the document as HTML (which is much more forgiving than XML).
+//
+// Namespaces maps the prefixes used in the expression to namespace URIs.  When it is empty, names are
+// matched on their local name alone and any namespace prefixes in the document are ignored.
+func XPath(data []byte, isHTML bool, expression string, namespaces map[string]string) (string, error) {
+	expr, err := xpath.CompileWithNS(expression, namespaces)
+	if err != nil {
+		return "", fmt.Errorf("%w '%s': %w", ErrInvalidExpression, expression, err)
+	}
+
+	var nav xpath.NodeNavigator
+	if isHTML {
+		doc, err := htmlquery.Parse(bytes.NewReader(data))
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+		}
+		nav = htmlquery.CreateXPathNavigator(doc)
+	} else {
+		doc, err := xmlquery.Parse(bytes.NewReader(data))
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+		}
+		nav = xmlquery.CreateXPathNavigator(doc)
+		if len(namespaces) == 0 {
+			nav = &localNameNavigator{NodeNavigator: nav}
+		}
+	}
+
+	switch result := expr.Evaluate(nav).(type) {
+	case *xpath.NodeIterator:
+		if !result.MoveNext() {
+			return "", fmt.Errorf("%w for '%s'", ErrNoMatch, expression)
+		}
+		return result.Current().Value(), nil
+	case float64:
+		return strconv.FormatFloat(result, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(result), nil
+	case string:
+		return result, nil
+	default:
+		return fmt.Sprint(result), nil
+	}
+}
+
+// localNameNavigator hides namespace prefixes so that names in the expression match on local name alone,
+// e.g. "//Envelope/Body" matches "<soap:Envelope><soap:Body>".
+type localNameNavigator struct {
+	xpath.NodeNavigator
+}
+
+func (n *localNameNavigator) Prefix() string {
+	return ""
+}
+
+func (n *localNameNavigator) Copy() xpath.NodeNavigator {
+	return &localNameNavigator{NodeNavigator: n.NodeNavigator.Copy()}
+}
+
+func (n *localNameNavigator) MoveTo(other xpath.NodeNavigator) bool {
+	if local, ok := other.(*localNameNavigator); ok {
+		other = local.NodeNavigator
+	}
+	return n.NodeNavigator.MoveTo(other)
+}
+
+// CSS returns the text content of the first element matching the selector, without surrounding whitespace.
+func CSS(data []byte, selector string) (string, error) {
+	sel, err := cascadia.Compile(selector)
+	if err != nil {
+		return "", fmt.Errorf("%w '%s': %w", ErrInvalidExpression, selector, err)
+	}
+	doc, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+	}
+
+	node := cascadia.Query(doc, sel)
+	if node == nil {
+		return "", fmt.Errorf("%w for '%s'", ErrNoMatch, selector)
+	}
+	var text strings.Builder
+	appendText(&text, node)
+	return strings.TrimSpace(text.String()), nil
+}
+
+func appendText(text *strings.Builder, node *html.Node) {
+	if node.Type == html.TextNode {
+		text.WriteString(node.Data)
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		appendText(text, child)
+	}
+}
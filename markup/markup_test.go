@@ -0,0 +1,91 @@
+package markup
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	testSoap = `<?xml version="1.0"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:m="urn:example">
+  <soap:Body>
+    <m:Result>42</m:Result>
+    <m:Item id="a">first</m:Item>
+    <m:Item id="b">second</m:Item>
+  </soap:Body>
+</soap:Envelope>`
+
+	testHtml = `<!DOCTYPE html>
+<html><head><title>
+  Status Page
+</title></head>
+<body><div id="status" class="ok">All <b>systems</b> go</div><p>one</p><p>two</p></body></html>`
+)
+
+var (
+	testNamespaces = map[string]string{"s": "http://schemas.xmlsoap.org/soap/envelope/", "x": "urn:example"}
+)
+
+var _ = Describe("Markup", func() {
+	DescribeTable("XPath",
+		func(data string, isHTML bool, namespaces map[string]string, expression string, expect string) {
+			// Act
+			actual, err := XPath([]byte(data), isHTML, expression, namespaces)
+
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actual).To(Equal(expect))
+		},
+		Entry("local names", testSoap, false, nil, "//Envelope/Body/Result/text()", "42"),
+		Entry("namespace prefixes", testSoap, false, testNamespaces, "/s:Envelope/s:Body/x:Result", "42"),
+		Entry("first match", testSoap, false, nil, "//Item", "first"),
+		Entry("predicate", testSoap, false, nil, "//Item[@id='b']", "second"),
+		Entry("attribute", testSoap, false, nil, "//Item[2]/@id", "b"),
+		Entry("number", testSoap, false, nil, "count(//Item)", "2"),
+		Entry("boolean", testSoap, false, nil, "count(//Item) > 1", "true"),
+		Entry("namespace-aware when configured", testSoap, false, testNamespaces, "count(//Item)", "0"),
+		Entry("html", testHtml, true, nil, "//div[@id='status']/b", "systems"),
+	)
+
+	DescribeTable("XPath errors",
+		func(data string, expression string, expect error) {
+			// Act
+			_, err := XPath([]byte(data), false, expression, testNamespaces)
+
+			// Assert
+			Expect(err).To(MatchError(expect))
+		},
+		Entry("no match", testSoap, "//Missing", ErrNoMatch),
+		Entry("invalid expression", testSoap, "//[", ErrInvalidExpression),
+		Entry("unknown prefix", testSoap, "//q:Result", ErrInvalidExpression),
+		Entry("invalid xml", "<a><b></a>", "//a", ErrInvalidDocument),
+	)
+
+	DescribeTable("CSS",
+		func(selector string, expect string) {
+			// Act
+			actual, err := CSS([]byte(testHtml), selector)
+
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actual).To(Equal(expect))
+		},
+		Entry("element", "title", "Status Page"),
+		Entry("id", "#status", "All systems go"),
+		Entry("class", "div.ok > b", "systems"),
+		Entry("first match", "p", "one"),
+		Entry("pseudo class", "p:nth-of-type(2)", "two"),
+	)
+
+	DescribeTable("CSS errors",
+		func(selector string, expect error) {
+			// Act
+			_, err := CSS([]byte(testHtml), selector)
+
+			// Assert
+			Expect(err).To(MatchError(expect))
+		},
+		Entry("no match", "table", ErrNoMatch),
+		Entry("invalid selector", "div[", ErrInvalidExpression),
+	)
+})
@@ -0,0 +1,101 @@
+package oauth2
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/keithpaterson/postal/config"
+)
+
+// memory caches tokens for the duration of the run
+var memory = &memoryCache{tokens: make(map[string]*Token)}
+
+type memoryCache struct {
+	mutex  sync.Mutex
+	tokens map[string]*Token
+}
+
+func (c *memoryCache) get(key string) *Token {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.tokens[key]
+}
+
+func (c *memoryCache) set(key string, token *Token) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.tokens[key] = token
+}
+
+func (c *memoryCache) reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	clear(c.tokens)
+}
+
+// diskCache stores tokens in files named after a hash of the configuration that produced them.
+type diskCache struct {
+	dir string
+}
+
+func newDiskCache(cfg config.OAuth2Config) *diskCache {
+	if cfg.NoCache {
+		return nil
+	}
+	dir := cfg.CacheDir
+	if dir == "" {
+		base, err := os.UserCacheDir()
+		if err != nil {
+			return nil
+		}
+		dir = filepath.Join(base, "postal", "oauth2")
+	}
+	return &diskCache{dir: dir}
+}
+
+// cacheKey identifies the token; the client secret and password are deliberately excluded
+// so that they do not influence (or leak through) the cache file name.
+func cacheKey(cfg config.OAuth2Config) string {
+	parts := []string{cfg.TokenURL, cfg.GrantType, cfg.ClientID, cfg.Audience, cfg.Username, strings.Join(cfg.Scopes, " ")}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *diskCache) load(key string) *Token {
+	if c == nil {
+		return nil
+	}
+	data, err := os.ReadFile(c.filename(key))
+	if err != nil {
+		return nil
+	}
+	var token Token
+	if err = json.Unmarshal(data, &token); err != nil {
+		return nil
+	}
+	return &token
+}
+
+func (c *diskCache) store(key string, token *Token) error {
+	// tokens without an expiry can't be invalidated, so they are not persisted
+	if c == nil || token.ExpiresAt.IsZero() {
+		return nil
+	}
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.filename(key), data, 0600)
+}
+
+func (c *diskCache) filename(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
@@ -0,0 +1,18 @@
+// package oauth2 fetches OAuth2 access tokens for requests
+//
+// Token data is pulled from the config.OAuth2Config structure, e.g.
+//
+//	cfg := config.OAuth2Config{TokenURL: "https://auth.example.com/token", GrantType: "client_credentials", ClientID: "me", ClientSecret: "..."}
+//	source := oauth2.NewTokenSource(cfg)
+//	token, err := source.Token()
+//
+// Use WithTLS to connect to the token endpoint with a custom TLS configuration (see cacert.FromConfig(...).TLSConfig()).
+//
+// Supported grants are "client_credentials", "password" and "refresh_token".
+//
+// Tokens are cached in memory for the run, and on disk until they expire (unless OAuth2Config.NoCache is set).
+// If a cached token has expired but includes a refresh token, the refresh token is used before falling
+// back to the configured grant.
+//
+// Assuming no errors, 'token.AccessToken' will contain the access token.
+package oauth2
@@ -0,0 +1,185 @@
+package oauth2
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/keithpaterson/postal/config"
+	"github.com/keithpaterson/postal/logging"
+
+	ulog "github.com/keithpaterson/resweave-utils/logging"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrNoTokenURL       = errors.New("oauth2 token-url not provided")
+	ErrInvalidGrantType = errors.New("invalid oauth2 grant type")
+	ErrMissingParameter = errors.New("missing oauth2 parameter")
+	ErrTokenRequest     = errors.New("oauth2 token request failed")
+)
+
+type tokenSource struct {
+	log    *zap.SugaredLogger
+	cfg    config.OAuth2Config
+	client *http.Client
+	cache  *diskCache
+}
+
+// NewTokenSource returns a factory used to fetch (or load a cached) access token using the config data.
+func NewTokenSource(cfg config.OAuth2Config) *tokenSource {
+	return &tokenSource{log: logging.NamedLogger("oauth2"), cfg: cfg, client: http.DefaultClient, cache: newDiskCache(cfg)}
+}
+
+// WithTLS uses tlsConfig (e.g. a private CA or client certificates) to connect to the token endpoint;
+// the default configuration is used if it is nil.
+func (s *tokenSource) WithTLS(tlsConfig *tls.Config) *tokenSource {
+	if tlsConfig != nil {
+		s.client = &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	}
+	return s
+}
+
+// Token returns a valid access token, fetching a new one from the token endpoint if necessary.
+func (s *tokenSource) Token() (*Token, error) {
+	if s.cfg.TokenURL == "" {
+		return nil, ErrNoTokenURL
+	}
+
+	key := cacheKey(s.cfg)
+	if token := memory.get(key); token.Valid() {
+		return token, nil
+	}
+
+	cached := s.cache.load(key)
+	if cached.Valid() {
+		s.log.Debugw("Token", ulog.LogKeyStatus, "using cached token")
+		memory.set(key, cached)
+		return cached, nil
+	}
+
+	var err error
+	var token *Token
+	if cached != nil && cached.RefreshToken != "" {
+		if token, err = s.fetch(s.refreshParams(cached.RefreshToken)); err != nil {
+			s.log.Debugw("Token", ulog.LogKeyStatus, "failed to refresh cached token", ulog.LogKeyError, err)
+			token = nil
+		}
+	}
+	if token == nil {
+		var params url.Values
+		if params, err = s.grantParams(); err != nil {
+			return nil, err
+		}
+		if token, err = s.fetch(params); err != nil {
+			return nil, err
+		}
+	}
+
+	memory.set(key, token)
+	if err = s.cache.store(key, token); err != nil {
+		// not fatal; we have a token, we just can't reuse it next time
+		s.log.Warnw("Token", ulog.LogKeyStatus, "failed to cache token", ulog.LogKeyError, err)
+	}
+	return token, nil
+}
+
+func (s *tokenSource) grantParams() (url.Values, error) {
+	params := url.Values{}
+	switch s.cfg.Grant() {
+	case config.GrantClientCredentials:
+		params.Set("grant_type", config.GrantClientCredentials.String())
+	case config.GrantPassword:
+		if s.cfg.Username == "" || s.cfg.Password == "" {
+			return nil, fmt.Errorf("%w: password grant requires username and password", ErrMissingParameter)
+		}
+		params.Set("grant_type", config.GrantPassword.String())
+		params.Set("username", s.cfg.Username)
+		params.Set("password", s.cfg.Password)
+	case config.GrantRefreshToken:
+		if s.cfg.RefreshToken == "" {
+			return nil, fmt.Errorf("%w: refresh_token grant requires refresh-token", ErrMissingParameter)
+		}
+		return s.refreshParams(s.cfg.RefreshToken), nil
+	default:
+		return nil, fmt.Errorf("%w '%s'", ErrInvalidGrantType, s.cfg.GrantType)
+	}
+	s.addOptionalParams(params)
+	return params, nil
+}
+
+func (s *tokenSource) refreshParams(refreshToken string) url.Values {
+	params := url.Values{}
+	params.Set("grant_type", config.GrantRefreshToken.String())
+	params.Set("refresh_token", refreshToken)
+	s.addOptionalParams(params)
+	return params
+}
+
+func (s *tokenSource) addOptionalParams(params url.Values) {
+	if len(s.cfg.Scopes) > 0 {
+		params.Set("scope", strings.Join(s.cfg.Scopes, " "))
+	}
+	if s.cfg.Audience != "" {
+		params.Set("audience", s.cfg.Audience)
+	}
+}
+
+func (s *tokenSource) fetch(params url.Values) (*Token, error) {
+	if s.cfg.AuthStyle == "params" && s.cfg.ClientID != "" {
+		params.Set("client_id", s.cfg.ClientID)
+		params.Set("client_secret", s.cfg.ClientSecret)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.cfg.TokenURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTokenRequest, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if s.cfg.AuthStyle != "params" && s.cfg.ClientID != "" {
+		req.SetBasicAuth(url.QueryEscape(s.cfg.ClientID), url.QueryEscape(s.cfg.ClientSecret))
+	}
+
+	s.log.Debugw("fetch", "token-url", s.cfg.TokenURL, "grant_type", params.Get("grant_type"))
+	now := time.Now()
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTokenRequest, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTokenRequest, err)
+	}
+
+	var data tokenResponse
+	if err = json.Unmarshal(body, &data); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("%w: invalid response: %w", ErrTokenRequest, err)
+	}
+	if resp.StatusCode != http.StatusOK || data.Error != "" {
+		return nil, fmt.Errorf("%w: %s: %s", ErrTokenRequest, resp.Status, data.describeError())
+	}
+	if data.AccessToken == "" {
+		return nil, fmt.Errorf("%w: response has no access_token", ErrTokenRequest)
+	}
+	return data.token(now), nil
+}
+
+func (r tokenResponse) describeError() string {
+	switch {
+	case r.Error == "":
+		return "no error information"
+	case r.ErrorDescription == "":
+		return r.Error
+	default:
+		return r.Error + " (" + r.ErrorDescription + ")"
+	}
+}
@@ -0,0 +1,191 @@
+package oauth2
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/keithpaterson/postal/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// tokenEndpoint is a stand-in token endpoint; it records the requests it receives
+type tokenEndpoint struct {
+	server   *httptest.Server
+	requests []*http.Request
+	response map[string]any
+	status   int
+}
+
+func newTokenEndpoint() *tokenEndpoint {
+	e := &tokenEndpoint{status: http.StatusOK, response: map[string]any{"access_token": "abc123", "token_type": "Bearer", "expires_in": 3600}}
+	e.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		e.requests = append(e.requests, r)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(e.status)
+		json.NewEncoder(w).Encode(e.response)
+	}))
+	return e
+}
+
+var _ = Describe("OAuth2", func() {
+	var (
+		endpoint *tokenEndpoint
+		cfg      config.OAuth2Config
+	)
+
+	BeforeEach(func() {
+		memory.reset()
+		endpoint = newTokenEndpoint()
+		cfg = config.OAuth2Config{
+			TokenURL:     endpoint.server.URL + "/token",
+			GrantType:    "client_credentials",
+			ClientID:     "client",
+			ClientSecret: "secret",
+			AuthStyle:    "basic",
+			CacheDir:     GinkgoT().TempDir(),
+		}
+	})
+	AfterEach(func() {
+		endpoint.server.Close()
+	})
+
+	DescribeTable("Token errors",
+		func(modify func(*config.OAuth2Config), expect error) {
+			// Arrange
+			modify(&cfg)
+
+			// Act
+			token, err := NewTokenSource(cfg).Token()
+
+			// Assert
+			Expect(err).To(MatchError(expect))
+			Expect(token).To(BeNil())
+		},
+		Entry("missing token url", func(c *config.OAuth2Config) { c.TokenURL = "" }, ErrNoTokenURL),
+		Entry("invalid grant", func(c *config.OAuth2Config) { c.GrantType = "implicit" }, ErrInvalidGrantType),
+		Entry("password grant without password", func(c *config.OAuth2Config) { c.GrantType = "password"; c.Username = "me" }, ErrMissingParameter),
+		Entry("refresh grant without refresh token", func(c *config.OAuth2Config) { c.GrantType = "refresh_token" }, ErrMissingParameter),
+		Entry("unreachable endpoint", func(c *config.OAuth2Config) { c.TokenURL = "http://127.0.0.1:1/token" }, ErrTokenRequest),
+	)
+
+	DescribeTable("Token grants",
+		func(modify func(*config.OAuth2Config), expect map[string]string) {
+			// Arrange
+			modify(&cfg)
+
+			// Act
+			token, err := NewTokenSource(cfg).Token()
+
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(token.AccessToken).To(Equal("abc123"))
+			Expect(token.TokenType).To(Equal("Bearer"))
+			Expect(endpoint.requests).To(HaveLen(1))
+			for key, value := range expect {
+				Expect(endpoint.requests[0].PostForm.Get(key)).To(Equal(value), key)
+			}
+		},
+		Entry("client credentials", func(c *config.OAuth2Config) {},
+			map[string]string{"grant_type": "client_credentials", "client_id": "", "client_secret": ""}),
+		Entry("client credentials with scopes and audience", func(c *config.OAuth2Config) { c.Scopes = []string{"read", "write"}; c.Audience = "api" },
+			map[string]string{"grant_type": "client_credentials", "scope": "read write", "audience": "api"}),
+		Entry("client credentials as params", func(c *config.OAuth2Config) { c.AuthStyle = "params" },
+			map[string]string{"grant_type": "client_credentials", "client_id": "client", "client_secret": "secret"}),
+		Entry("password", func(c *config.OAuth2Config) { c.GrantType = "password"; c.Username = "me"; c.Password = "pw" },
+			map[string]string{"grant_type": "password", "username": "me", "password": "pw"}),
+		Entry("refresh token", func(c *config.OAuth2Config) { c.GrantType = "refresh_token"; c.RefreshToken = "refresh" },
+			map[string]string{"grant_type": "refresh_token", "refresh_token": "refresh"}),
+	)
+
+	It("sends client credentials using basic auth", func() {
+		// Act
+		_, err := NewTokenSource(cfg).Token()
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		user, password, ok := endpoint.requests[0].BasicAuth()
+		Expect(ok).To(BeTrue())
+		Expect(user).To(Equal("client"))
+		Expect(password).To(Equal("secret"))
+	})
+
+	It("reports token endpoint errors", func() {
+		// Arrange
+		endpoint.status = http.StatusUnauthorized
+		endpoint.response = map[string]any{"error": "invalid_client", "error_description": "bad secret"}
+
+		// Act
+		_, err := NewTokenSource(cfg).Token()
+
+		// Assert
+		Expect(err).To(MatchError(ErrTokenRequest))
+		Expect(err.Error()).To(ContainSubstring("invalid_client (bad secret)"))
+	})
+
+	It("caches the token on disk until it expires", func() {
+		// Arrange
+		_, err := NewTokenSource(cfg).Token()
+		Expect(err).ToNot(HaveOccurred())
+		memory.reset()
+
+		// Act
+		token, err := NewTokenSource(cfg).Token()
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token.AccessToken).To(Equal("abc123"))
+		Expect(endpoint.requests).To(HaveLen(1))
+	})
+
+	It("fetches a new token when the cached token has expired", func() {
+		// Arrange
+		endpoint.response["expires_in"] = 1 // expires within the expiry margin
+		_, err := NewTokenSource(cfg).Token()
+		Expect(err).ToNot(HaveOccurred())
+		memory.reset()
+
+		// Act
+		_, err = NewTokenSource(cfg).Token()
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(endpoint.requests).To(HaveLen(2))
+	})
+
+	It("uses the refresh token from an expired cached token", func() {
+		// Arrange
+		endpoint.response["expires_in"] = 1
+		endpoint.response["refresh_token"] = "refresh-me"
+		_, err := NewTokenSource(cfg).Token()
+		Expect(err).ToNot(HaveOccurred())
+		memory.reset()
+
+		// Act
+		_, err = NewTokenSource(cfg).Token()
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(endpoint.requests).To(HaveLen(2))
+		Expect(endpoint.requests[1].PostForm.Get("grant_type")).To(Equal("refresh_token"))
+		Expect(endpoint.requests[1].PostForm.Get("refresh_token")).To(Equal("refresh-me"))
+	})
+
+	It("does not use the disk cache when disabled", func() {
+		// Arrange
+		cfg.NoCache = true
+		_, err := NewTokenSource(cfg).Token()
+		Expect(err).ToNot(HaveOccurred())
+		memory.reset()
+
+		// Act
+		_, err = NewTokenSource(cfg).Token()
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(endpoint.requests).To(HaveLen(2))
+	})
+})
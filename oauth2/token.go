@@ -0,0 +1,44 @@
+package oauth2
+
+import (
+	"time"
+)
+
+// tokens are considered expired a little early to allow for clock skew and request latency
+const expiryMargin = 30 * time.Second
+
+// Token is the access token issued by the token endpoint.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	TokenType    string    `json:"token_type,omitempty"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at,omitempty"`
+}
+
+// tokenResponse is the token endpoint's response body (RFC 6749 section 5)
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Valid returns true if the token has an access token that has not expired.
+//
+// Tokens without an expiry time are valid for the current run only (they are never cached on disk).
+func (t *Token) Valid() bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	return t.ExpiresAt.IsZero() || time.Now().Add(expiryMargin).Before(t.ExpiresAt)
+}
+
+func (r tokenResponse) token(now time.Time) *Token {
+	token := &Token{AccessToken: r.AccessToken, TokenType: r.TokenType, RefreshToken: r.RefreshToken}
+	if r.ExpiresIn > 0 {
+		token.ExpiresAt = now.Add(time.Duration(r.ExpiresIn) * time.Second)
+	}
+	return token
+}
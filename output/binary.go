@@ -0,0 +1,78 @@
+package output
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/keithpaterson/postal/config"
+)
+
+var (
+	ErrBinaryToTerminal = errors.New("refusing to write binary output to a terminal (use --force-binary to override)")
+)
+
+// binaryOutputter streams the body unchanged; unlike 'raw' it protects the terminal from binary data.
+type binaryOutputter struct {
+	force  bool
+	writer io.Writer
+}
+
+func newBinaryOutputter(cfg config.OutputConfig, writer io.Writer) *binaryOutputter {
+	return &binaryOutputter{force: cfg.ForceBinary, writer: writer}
+}
+
+func (o *binaryOutputter) Write(resp *http.Response) error {
+	if !o.force && isTerminal(o.writer) {
+		return ErrBinaryToTerminal
+	}
+	_, err := io.Copy(o.writer, resp.Body)
+	return err
+}
+
+// base64Outputter writes the body base64-encoded on a single line.
+type base64Outputter struct {
+	writer io.Writer
+}
+
+func newBase64Outputter(_ config.OutputConfig, writer io.Writer) *base64Outputter {
+	return &base64Outputter{writer: writer}
+}
+
+func (o *base64Outputter) Write(resp *http.Response) error {
+	encoder := base64.NewEncoder(base64.StdEncoding, o.writer)
+	if _, err := io.Copy(encoder, resp.Body); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(o.writer, "\n")
+	return err
+}
+
+// hexOutputter writes the body as a hex dump in the same format as 'hexdump -C', including the final
+// line with the size of the body (repeated lines are not collapsed to '*', though).
+type hexOutputter struct {
+	writer io.Writer
+}
+
+func newHexOutputter(_ config.OutputConfig, writer io.Writer) *hexOutputter {
+	return &hexOutputter{writer: writer}
+}
+
+func (o *hexOutputter) Write(resp *http.Response) error {
+	dumper := hex.Dumper(o.writer)
+	size, err := io.Copy(dumper, resp.Body)
+	if err != nil {
+		return err
+	}
+	if err = dumper.Close(); err != nil || size == 0 {
+		return err
+	}
+	_, err = fmt.Fprintf(o.writer, "%08x\n", size)
+	return err
+}
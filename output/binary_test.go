@@ -0,0 +1,72 @@
+package output
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/keithpaterson/postal/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Binary Output", func() {
+	var body = []byte("postal\x00\x01\x02\xff binary body!")
+
+	DescribeTable("Write",
+		func(format string, expect string) {
+			// Arrange
+			var buf bytes.Buffer
+			cfg := config.NewConfig()
+			cfg.Output.Format = format
+			var o Outputter
+			switch cfg.Output.OutFormat() {
+			case config.OutFmtBase64:
+				o = newBase64Outputter(cfg.Output, &buf)
+			case config.OutFmtHex:
+				o = newHexOutputter(cfg.Output, &buf)
+			case config.OutFmtBinary:
+				o = newBinaryOutputter(cfg.Output, &buf)
+			}
+
+			// Act
+			err := o.Write(newTestResponse("", string(body)))
+
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(buf.String()).To(Equal(expect))
+		},
+		Entry("base64", "base64", "cG9zdGFsAAEC/yBiaW5hcnkgYm9keSE=\n"),
+		Entry("hex", "hex", "00000000  70 6f 73 74 61 6c 00 01  02 ff 20 62 69 6e 61 72  |postal.... binar|\n"+
+			"00000010  79 20 62 6f 64 79 21                              |y body!|\n"+
+			"00000017\n"),
+		Entry("binary", "binary", string(body)),
+	)
+
+	It("writes binary to files", func() {
+		// Arrange
+		file, err := os.CreateTemp(GinkgoT().TempDir(), "out")
+		Expect(err).ToNot(HaveOccurred())
+		defer file.Close()
+		o := newBinaryOutputter(config.OutputConfig{}, file)
+
+		// Act
+		err = o.Write(newTestResponse("", string(body)))
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.ReadFile(file.Name())).To(Equal(body))
+	})
+	It("writes nothing for an empty hex body", func() {
+		// Arrange
+		var buf bytes.Buffer
+		o := newHexOutputter(config.OutputConfig{}, &buf)
+
+		// Act
+		err := o.Write(newTestResponse("", ""))
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buf.String()).To(BeEmpty())
+	})
+})
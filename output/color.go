@@ -0,0 +1,146 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	"github.com/keithpaterson/postal/config"
+)
+
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiRed     = "\x1b[31m"
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+	ansiBlue    = "\x1b[34m"
+	ansiMagenta = "\x1b[35m"
+	ansiCyan    = "\x1b[36m"
+)
+
+// colorizer adds ANSI colors to status lines, headers and JSON; when disabled it changes nothing
+type colorizer struct {
+	enabled bool
+}
+
+func newColorizer(cfg config.OutputConfig, writer io.Writer) *colorizer {
+	switch cfg.ColorMode() {
+	case config.ColorAlways:
+		return &colorizer{enabled: true}
+	case config.ColorNever:
+		return &colorizer{enabled: false}
+	default:
+		_, noColor := os.LookupEnv("NO_COLOR")
+		return &colorizer{enabled: !noColor && isTerminal(writer)}
+	}
+}
+
+// isTerminal is true when the writer is a character device, i.e. not a regular file or a pipe
+func isTerminal(writer io.Writer) bool {
+	file, ok := writer.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func (c *colorizer) paint(color string, text string) string {
+	if c == nil || !c.enabled || text == "" {
+		return text
+	}
+	return color + text + ansiReset
+}
+
+func (c *colorizer) Status(code int, line string) string {
+	switch {
+	case code >= 500:
+		return c.paint(ansiBold+ansiRed, line)
+	case code >= 400:
+		return c.paint(ansiBold+ansiYellow, line)
+	case code >= 300:
+		return c.paint(ansiBold+ansiCyan, line)
+	default:
+		return c.paint(ansiBold+ansiGreen, line)
+	}
+}
+
+func (c *colorizer) HeaderName(name string) string {
+	return c.paint(ansiCyan, name)
+}
+
+// Json colors the keys, strings, numbers and literals of a JSON document.
+// The input is not validated; anything unexpected is copied unchanged.
+func (c *colorizer) Json(data []byte) []byte {
+	if c == nil || !c.enabled {
+		return data
+	}
+
+	var buf bytes.Buffer
+	for pos := 0; pos < len(data); {
+		ch := data[pos]
+		switch {
+		case ch == '"':
+			end := jsonStringEnd(data, pos)
+			color := ansiGreen
+			if isJsonKey(data, end) {
+				color = ansiBold + ansiBlue
+			}
+			buf.WriteString(c.paint(color, string(data[pos:end])))
+			pos = end
+		case ch == '-' || (ch >= '0' && ch <= '9'):
+			end := jsonTokenEnd(data, pos)
+			buf.WriteString(c.paint(ansiCyan, string(data[pos:end])))
+			pos = end
+		case ch == 't' || ch == 'f' || ch == 'n':
+			end := jsonTokenEnd(data, pos)
+			buf.WriteString(c.paint(ansiMagenta, string(data[pos:end])))
+			pos = end
+		default:
+			buf.WriteByte(ch)
+			pos++
+		}
+	}
+	return buf.Bytes()
+}
+
+// jsonStringEnd returns the position following the closing quote of the string starting at pos
+func jsonStringEnd(data []byte, pos int) int {
+	for index := pos + 1; index < len(data); index++ {
+		switch data[index] {
+		case '\\':
+			index++
+		case '"':
+			return index + 1
+		}
+	}
+	return len(data)
+}
+
+func jsonTokenEnd(data []byte, pos int) int {
+	for index := pos; index < len(data); index++ {
+		switch data[index] {
+		case ',', ':', ']', '}', ' ', '\t', '\r', '\n':
+			return index
+		}
+	}
+	return len(data)
+}
+
+func isJsonKey(data []byte, pos int) bool {
+	for _, ch := range data[pos:] {
+		switch ch {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case ':':
+			return true
+		default:
+			return false
+		}
+	}
+	return false
+}
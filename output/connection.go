@@ -0,0 +1,65 @@
+package output
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNoTLS            = errors.New("not a TLS connection")
+	ErrNoPeer           = errors.New("no peer certificate")
+	ErrUnknownTLSDetail = errors.New("unknown tls detail")
+)
+
+// tlsDetail returns the named detail of the TLS connection; peer details describe the server's (leaf) certificate
+func tlsDetail(state *tls.ConnectionState, name string) (string, error) {
+	if state == nil {
+		return "", ErrNoTLS
+	}
+
+	switch strings.ToLower(name) {
+	case "version":
+		return tls.VersionName(state.Version), nil
+	case "cipher":
+		return tls.CipherSuiteName(state.CipherSuite), nil
+	case "alpn":
+		return state.NegotiatedProtocol, nil
+	case "server-name":
+		return state.ServerName, nil
+	case "peer-subject", "peer-issuer", "peer-not-before", "peer-not-after", "peer-sans":
+		return peerDetail(state, strings.ToLower(name))
+	default:
+		return "", fmt.Errorf("%w: '%s'", ErrUnknownTLSDetail, name)
+	}
+}
+
+func peerDetail(state *tls.ConnectionState, name string) (string, error) {
+	if len(state.PeerCertificates) == 0 {
+		return "", ErrNoPeer
+	}
+	cert := state.PeerCertificates[0]
+
+	switch name {
+	case "peer-subject":
+		return cert.Subject.String(), nil
+	case "peer-issuer":
+		return cert.Issuer.String(), nil
+	case "peer-not-before":
+		return cert.NotBefore.UTC().Format(time.RFC3339), nil
+	case "peer-not-after":
+		return cert.NotAfter.UTC().Format(time.RFC3339), nil
+	default: // peer-sans
+		sans := append([]string{}, cert.DNSNames...)
+		for _, ip := range cert.IPAddresses {
+			sans = append(sans, ip.String())
+		}
+		sans = append(sans, cert.EmailAddresses...)
+		for _, uri := range cert.URIs {
+			sans = append(sans, uri.String())
+		}
+		return strings.Join(sans, ","), nil
+	}
+}
@@ -4,7 +4,134 @@ package output contains tools for outputting the response.
 For supported output types, refer to the [config] package
 
 This package will resolve the template string before writing 'text' output.
-The template is ignored when using the 'raw' output format.
+The template is only used by the 'text' output format.
+
+# Filenames:
+
+[output] filename (and the filename of each sink) is resolved just like a template, so it can use
+properties, dates and ${response:...} tokens, e.g. "out/${prop:env}-${response:status-code}.json".
+${output:counter} is replaced with the lowest number (starting at 1) that gives a file that does not exist yet,
+so that repeated runs of "out/${prop:env}-${output:counter}.json" don't overwrite each other.
+
+The file is only created when the first byte is written to it (or, for an empty body, once the response was
+processed without errors), so failed requests and templates that cannot be resolved don't leave empty files behind.
+
+# Sinks:
+
+By default the response is written to [output] filename.  To write it to several destinations at once,
+configure [[output.sinks]] instead; each sink has its own format, template, engine and filename, and falls
+back to the [output] settings for anything it does not specify:
+
+	[[output.sinks]]
+	format = "raw"
+	filename = "out/resp.bin"
+
+	[[output.sinks]]
+	template = "${response:status-code} ${response:header=Content-Type}\n"
+	filename = "runs.log"
+	append = true
+
+	[[output.sinks]]
+	template = "saved ${response:content-length} bytes\n"
+	filename = "stdout"
+
+Files are replaced unless append is true, and missing parent directories are created.
+When sinks are used the body is copied into a temporary file (which is removed afterwards) so that every sink
+can read it; formats that stream the body (e.g. 'binary') still don't hold it in memory.  Formats that need
+the whole body (templates, 'json', 'har') read it into memory as usual.
+
+# HAR Output:
+
+The 'har' format (or 'postal send --har filename', which adds a sink next to the regular output) writes
+an HTTP Archive (HAR 1.2) that can be imported into browser developer tools.  Every hop (i.e. each redirect)
+is a separate entry, with the request's headers, query and post data, and the response's headers.
+Only the final entry has the response content and timings; lookups, connections and handshakes made for
+redirects are included in its timings.  Sensitive headers (including Set-Cookie) and cookie values are masked
+unless --show-secrets is used.
+
+# Binary Output:
+
+Bodies that are not text (images, protobuf payloads, signed blobs) can be written safely using:
+  - 'base64': the body is base64-encoded (standard alphabet, padded) on a single line
+  - 'hex': the body is written as a hex dump, in the same format as 'hexdump -C' (ending with the size of the
+    body; repeated lines are not collapsed)
+  - 'binary': the body is streamed unchanged, without being held in memory.  To protect your terminal,
+    'binary' refuses to write to a terminal unless [output] force-binary (or --force-binary) is set.
+
+# Template Sources:
+
+The template can be written inline, loaded from a file, or selected by name:
+  - template = "${response:status}\n${response:body}": an inline template
+  - template = "file:templates/summary.tmpl": the template is read from the file
+  - template = "@summary": the template named "summary" in the [output.templates] table,
+    which can itself be inline or "file:..."; use 'postal send -t @summary' on the command line
+
+Like other "file:" values (e.g. request bodies and certificates), template files are relative to the current
+directory, not to the config file that names them.
+
+Inline templates expand "\n" to a newline and "\t" to [output] tab-width spaces (default 4; 0 writes a tab).
+Set [output] no-escapes = true to disable this.  Templates loaded from files are used exactly as written.
+For the go engine only text outside of {{ }} actions is expanded; escapes in string literals inside an action
+(e.g. {{ printf "%d\n" .Status }}) are handled by go itself.
+
+# Go Templates:
+
+When [output] engine = "go", the template is a golang text/template instead of a ${...} template,
+which allows loops, conditions and formatting.  The default template is "{{ .Response.Body }}".
+
+The data available to the template is:
+  - .Request: the .Method, .URL, .Headers, .Body and .Attempt of the (final) request; sensitive headers are masked
+  - .Response: the .Status, .StatusCode, .Proto, .Headers, .ContentLength and .Body (a string) of the response
+  - .Status: the response status code, e.g. {{ if ge .Status 400 }}failed{{ end }}
+  - .Headers: the response headers, e.g. {{ range $name, $values := .Headers }}{{ $name }}={{ index $values 0 }};{{ end }}
+  - .JSON: the parsed body when the response content type is JSON (otherwise nil).
+    Numbers are kept exactly as they were received, so compare them as strings: {{ if eq (print .JSON.count) "0" }}
+  - .Properties: the configured properties
+  - .TLS: the golang tls.ConnectionState (nil for plain http), e.g. {{ with .TLS }}{{ (index .PeerCertificates 0).NotAfter }}{{ end }}
+  - .RemoteAddr: the address (ip:port) of the server that sent the response
+
+In addition to the standard text/template functions, the following are available:
+  - json: the value as compact JSON, e.g. {{ json .JSON.items }}
+  - toPrettyJSON: the value as indented JSON
+  - upper, lower: change the case of a string
+  - default: a fallback for missing or empty values, e.g. {{ .JSON.name | default "unknown" }}
+  - b64dec: decode a base64 string
+  - header: the value of a response header, e.g. {{ header "Content-Type" }}
+
+# Pretty Printing and Colors:
+
+When [output] pretty is true (or 'postal send --pretty' is used), JSON and XML response bodies are
+reformatted based on the Content-Type before they are written by the 'text' (${response:body}) and
+'verbose' formats.  Bodies that cannot be parsed are written as-is.
+
+[output] color (or 'postal send --color') controls ANSI colorization of status lines and headers
+('verbose' format) and JSON bodies:
+  - "auto": colors are only used when writing to a terminal and the NO_COLOR environment variable is not set;
+    files and pipes are never colored
+  - "always": colors are always used
+  - "never": colors are never used
+
+# JSON Output:
+
+The 'json' output format writes a single JSON document describing the exchange:
+  - request: the method, final URL (after redirects) and headers (sensitive values are masked)
+  - response: status, status_code, proto, headers (as a multi-map), content_length and the body
+  - timings: the start time plus dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms, response_ms and total_ms
+    (see ${response:timing=...} below)
+
+The body is embedded as JSON when the content type is JSON (application/json or *+json);
+otherwise it is a string, or base64-encoded when it is not valid UTF-8.  The body_encoding
+field ("json", "text" or "base64") tells you which.
+
+# Verbose Output:
+
+The 'verbose' output format (also selected by 'postal send -v') writes everything about the exchange
+in the style of 'curl -v', using the requests that were actually sent:
+  - "* " lines: TLS handshake details and redirects
+  - "> " lines: the request line, headers (sensitive values are masked) and a preview of the body
+  - "< " lines: the response status line and headers
+
+Every redirect hop is shown, followed by the final response body.
 
 # Output Templates:
 
@@ -29,5 +156,44 @@ Supported response token resplacements are:
   - ${response:status}: the status string for the response as supplied by golang
   - ${response:status-code}: the status code number for the response
   - ${response:content-length}: shortcut for extracting just the content-length value from the headers
+  - ${response:json=path}: the value found at "path" in a JSON response body, e.g. ${response:json=data.items[0].id}.
+    Strings, numbers and booleans are written as plain text; arrays and objects as compact JSON.
+    Paths support array indexes ("[0]", "[-1]"), wildcards ("[*]" or "*") and "length()";
+    see the [jsonpath] package for details.
+  - ${response:json-raw=path}: as above, but the value is always written as JSON (so strings keep their quotes)
+  - ${response:xpath=expression}: the result of an XPath 1.0 expression applied to an XML response body,
+    e.g. ${response:xpath=//Envelope/Body/Result/text()}.  HTML bodies (content type text/html) are also supported.
+    Names match on their local name (ignoring the document's namespace prefixes) unless namespace prefixes
+    are configured using "namespaces" in the [output] section, in which case matching is namespace-aware.
+  - ${response:css=selector}: the text content of the first element matching a CSS selector in an HTML
+    response body, e.g. ${response:css=title}
+  - ${response:timing=name}: how long part of the exchange took, in milliseconds (e.g. "12.345"), where "name" is one of:
+    "dns" (host name lookups), "connect" (TCP connections), "tls" (TLS handshakes),
+    "ttfb" (from the request being written until the first response byte, i.e. server time),
+    "transfer" (reading the body), "response" (from the start until the response headers arrived)
+    or "total".  Lookups, connections and handshakes are summed over redirects.
+  - ${response:tls=name}: details of the TLS connection that delivered the response, where "name" is one of:
+    "version" (e.g. "TLS 1.3"), "cipher", "alpn" (the negotiated protocol, e.g. "h2"), "server-name",
+    "peer-subject", "peer-issuer", "peer-not-before", "peer-not-after" (RFC3339, UTC) and "peer-sans"
+    (the certificate's subject alternative names, separated by commas).  Peer details describe the
+    server's own (leaf) certificate.  Using these with a plain http response is an error.
+  - ${response:remote-addr}: the address (ip:port) of the server that sent the response; also available as ${response:tls=remote-addr}
+
+The request that was sent (after tokens were resolved and it was signed) is available as well;
+when redirects were followed, these describe the final request:
+  - ${request:method}, ${request:url}, ${request:path}, ${request:query}, ${request:host}: parts of the request
+  - ${request:header=xxx}: the request header specified by "xxx"; sensitive headers are masked unless --show-secrets is used
+  - ${request:body}, ${request:content-length}: the request body and its length
+  - ${request:attempt}: how many times the request was sent, starting at 1 (redirects don't count as new attempts)
+
+See the [markup] package for more information about xpath and css expressions.
+
+Token values can be passed through filters, e.g. ${response:header=Location | trim | urlencode} or
+${response:body | sha256}; see the [resolver] package for the list of filters.  A failed filter is an error,
+unless the first filter is "default", e.g. ${response:json=data.name | default(unknown)}, which also
+covers json paths, xpaths and css selectors that cannot be found.
+
+If a json path, xpath or css selector cannot be found, or the body cannot be parsed, nothing is written
+and the error describes what failed.  The body is only read once, no matter how many tokens use it.
 */
 package output
@@ -0,0 +1,149 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/keithpaterson/postal/config"
+)
+
+const (
+	// counterToken is replaced with the lowest number (from 1) that gives a filename that does not exist yet
+	counterToken = "${output:counter}"
+	maxCounter   = 10000
+)
+
+var (
+	ErrInvalidFilename = errors.New("invalid output filename")
+)
+
+// fileWriter writes to the output file, which is created when the first byte is written; this way
+// failures (e.g. a template that cannot be resolved) don't leave empty files behind.
+type fileWriter struct {
+	cfg      config.OutputConfig
+	template *template
+
+	filename string
+	flags    int
+	file     *os.File
+}
+
+func newFileWriter(cfg config.OutputConfig, template *template) *fileWriter {
+	return &fileWriter{cfg: cfg, template: template}
+}
+
+// resolve determines the filename for the response: ${...} tokens are resolved just like they are in the template,
+// and ${output:counter} picks a name that doesn't already exist.
+func (w *fileWriter) resolve(resp *http.Response) error {
+	name := w.cfg.Filename
+	if strings.Contains(strings.ReplaceAll(name, counterToken, ""), "${") {
+		// the filename may use the body, which the outputter still needs to read
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		tokenResp := *resp
+		tokenResp.Body = io.NopCloser(bytes.NewReader(body))
+		if name, err = w.template.resolve(name, &tokenResp); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidFilename, err)
+		}
+	}
+
+	w.flags = os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if w.cfg.Append {
+		w.flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+
+	if strings.Contains(name, counterToken) {
+		var err error
+		if name, err = nextFilename(name); err != nil {
+			return err
+		}
+		// never overwrite a file that appeared after we checked
+		w.flags |= os.O_EXCL
+	}
+
+	if name = strings.TrimSpace(name); name == "" {
+		return fmt.Errorf("%w: '%s' resolves to an empty name", ErrInvalidFilename, w.cfg.Filename)
+	}
+	w.filename = name
+	return nil
+}
+
+func (w *fileWriter) Write(data []byte) (int, error) {
+	if w.file == nil {
+		if err := w.open(); err != nil {
+			return 0, err
+		}
+	}
+	return w.file.Write(data)
+}
+
+// finish closes the file; if nothing was written but the output succeeded (e.g. an empty body), the file is still created.
+func (w *fileWriter) finish(succeeded bool) error {
+	if w.file == nil {
+		if !succeeded || w.filename == "" {
+			return nil
+		}
+		if err := w.open(); err != nil {
+			return err
+		}
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+func (w *fileWriter) open() error {
+	if w.filename == "" {
+		return fmt.Errorf("%w: filename has not been resolved", ErrInvalidFilename)
+	}
+	if err := os.MkdirAll(filepath.Dir(w.filename), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(w.filename, w.flags, 0o666)
+	if err != nil {
+		return err
+	}
+	w.file = file
+	return nil
+}
+
+func nextFilename(pattern string) (string, error) {
+	for counter := 1; counter <= maxCounter; counter++ {
+		name := strings.ReplaceAll(pattern, counterToken, strconv.Itoa(counter))
+		if _, err := os.Stat(name); errors.Is(err, fs.ErrNotExist) {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("%w: no unused filename for '%s'", ErrInvalidFilename, pattern)
+}
+
+// fileOutputter resolves the filename for each response before the outputter writes to it.
+type fileOutputter struct {
+	outputter Outputter
+	writer    *fileWriter
+}
+
+func newFileOutputter(outputter Outputter, writer *fileWriter) *fileOutputter {
+	return &fileOutputter{outputter: outputter, writer: writer}
+}
+
+func (o *fileOutputter) Write(resp *http.Response) error {
+	if err := o.writer.resolve(resp); err != nil {
+		return err
+	}
+	err := o.outputter.Write(resp)
+	return errors.Join(err, o.writer.finish(err == nil))
+}
@@ -0,0 +1,85 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/keithpaterson/postal/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("File Output", func() {
+	var dir string
+	var cfg *config.Config
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		cfg = config.NewConfig()
+		cfg.Properties["env"] = "qa"
+	})
+
+	It("resolves tokens in the filename", func() {
+		// Arrange
+		cfg.Output.Filename = filepath.Join(dir, "${prop:env}", "${response:status-code}-${response:json=id}.json")
+
+		// Act
+		err := NewOutputter(cfg).Write(newTestResponse("application/json", `{"id":"abc"}`))
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.ReadFile(filepath.Join(dir, "qa", "200-abc.json"))).To(BeEquivalentTo(`{"id":"abc"}`))
+	})
+
+	It("uses the counter to avoid overwriting files", func() {
+		// Arrange
+		cfg.Output.Filename = filepath.Join(dir, "${prop:env}-${output:counter}.json")
+
+		// Act
+		err1 := NewOutputter(cfg).Write(newTestResponse("application/json", `1`))
+		err2 := NewOutputter(cfg).Write(newTestResponse("application/json", `2`))
+
+		// Assert
+		Expect(err1).ToNot(HaveOccurred())
+		Expect(err2).ToNot(HaveOccurred())
+		Expect(os.ReadFile(filepath.Join(dir, "qa-1.json"))).To(BeEquivalentTo(`1`))
+		Expect(os.ReadFile(filepath.Join(dir, "qa-2.json"))).To(BeEquivalentTo(`2`))
+	})
+
+	It("does not create the file when the output fails", func() {
+		// Arrange
+		cfg.Output.Filename = filepath.Join(dir, "out.txt")
+		cfg.Output.Template = "${response:json=missing}"
+
+		// Act
+		err := NewOutputter(cfg).Write(newTestResponse("application/json", `{}`))
+
+		// Assert
+		Expect(err).To(MatchError(ErrResolveFailed))
+		Expect(filepath.Join(dir, "out.txt")).ToNot(BeAnExistingFile())
+	})
+
+	It("creates the file for an empty body", func() {
+		// Arrange
+		cfg.Output.Filename = filepath.Join(dir, "out.bin")
+		cfg.Output.Format = "raw"
+
+		// Act
+		err := NewOutputter(cfg).Write(newTestResponse("application/json", ``))
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.ReadFile(filepath.Join(dir, "out.bin"))).To(BeEmpty())
+	})
+
+	It("reports filenames that cannot be resolved", func() {
+		// Arrange
+		cfg.Output.Filename = filepath.Join(dir, "${response:json=missing}.json")
+
+		// Act
+		err := NewOutputter(cfg).Write(newTestResponse("application/json", `{}`))
+
+		// Assert
+		Expect(err).To(MatchError(ErrInvalidFilename))
+	})
+})
@@ -0,0 +1,165 @@
+package output
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	gotemplate "text/template"
+
+	"github.com/keithpaterson/postal/config"
+	"github.com/keithpaterson/postal/jsonpath"
+	"github.com/keithpaterson/postal/redact"
+	"github.com/keithpaterson/postal/trace"
+)
+
+// templateData is the data model available to "go" engine templates
+type templateData struct {
+	Request    templateRequest
+	Response   templateResponse
+	Status     int
+	Headers    http.Header
+	JSON       any
+	Properties config.Properties
+	TLS        *tls.ConnectionState
+	RemoteAddr string
+}
+
+type templateRequest struct {
+	Method  string
+	URL     string
+	Headers http.Header
+	Body    string
+	Attempt int
+}
+
+type templateResponse struct {
+	Status        string
+	StatusCode    int
+	Proto         string
+	Headers       http.Header
+	ContentLength int64
+	Body          string
+}
+
+func (t *template) applyGo(input string, resp *http.Response) (string, error) {
+	tmpl, err := gotemplate.New("output").Funcs(t.goFuncs(resp)).Parse(input)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+	}
+
+	data, err := t.goData(resp)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+	}
+	return buf.String(), nil
+}
+
+func (t *template) goData(resp *http.Response) (*templateData, error) {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &templateData{
+		Response: templateResponse{
+			Status:        resp.Status,
+			StatusCode:    resp.StatusCode,
+			Proto:         resp.Proto,
+			Headers:       resp.Header,
+			ContentLength: resp.ContentLength,
+			Body:          string(body),
+		},
+		Status:     resp.StatusCode,
+		Headers:    resp.Header,
+		Properties: t.cfg.Properties,
+		TLS:        resp.TLS,
+	}
+	if tr := trace.FromResponse(resp); tr != nil {
+		data.RemoteAddr = tr.RemoteAddr()
+	}
+	if req := resp.Request; req != nil {
+		reqBody, _ := readRequestBody(req)
+		data.Request = templateRequest{
+			Method:  req.Method,
+			URL:     req.URL.String(),
+			Headers: redact.New(t.cfg).Headers(req.Header),
+			Body:    string(reqBody),
+			Attempt: requestAttempt(resp),
+		}
+	}
+	// .JSON is only available when the body is JSON
+	if contentKindOf(resp.Header.Get("content-type")) == contentJson {
+		if data.JSON, err = jsonpath.Decode(body); err != nil {
+			t.log.Warnw("applyGo", "warning", "failed to decode JSON body", "error", err)
+		}
+	}
+	return data, nil
+}
+
+func (t *template) goFuncs(resp *http.Response) gotemplate.FuncMap {
+	return gotemplate.FuncMap{
+		"json":         templateJson,
+		"toPrettyJSON": templatePrettyJson,
+		"upper":        strings.ToUpper,
+		"lower":        strings.ToLower,
+		"default":      templateDefault,
+		"b64dec":       templateBase64Decode,
+		"header": func(name string) string {
+			return strings.Join(resp.Header.Values(name), ",")
+		},
+	}
+}
+
+func templateJson(value any) (string, error) {
+	return jsonpath.Raw(value)
+}
+
+func templatePrettyJson(value any) (string, error) {
+	data, err := json.MarshalIndent(value, "", prettyIndent)
+	return string(data), err
+}
+
+// templateDefault returns value, or fallback if value is missing or empty; use it as '{{ .JSON.name | default "unknown" }}'
+func templateDefault(fallback any, value ...any) any {
+	if len(value) == 0 || isEmpty(value[0]) {
+		return fallback
+	}
+	return value[0]
+}
+
+func isEmpty(value any) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	default:
+		return v.IsZero()
+	}
+}
+
+func templateBase64Decode(value string) (string, error) {
+	value = strings.TrimRight(strings.TrimSpace(value), "=")
+	data, err := base64.RawStdEncoding.DecodeString(value)
+	if err != nil {
+		if data, err = base64.RawURLEncoding.DecodeString(value); err != nil {
+			return "", fmt.Errorf("b64dec: invalid base64 data")
+		}
+	}
+	return string(data), nil
+}
@@ -0,0 +1,78 @@
+package output
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+
+	"github.com/keithpaterson/postal/config"
+	"github.com/keithpaterson/postal/logging"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Go Template", func() {
+	apply := func(template string, body string) (string, error) {
+		cfg := config.NewConfig()
+		cfg.Output.Engine = "go"
+		cfg.Output.Template = template
+		cfg.Properties["env"] = "test"
+		resp := newTestResponse("application/json", body)
+		resp.Header.Add("X-Multi", "one")
+		resp.Header.Add("X-Multi", "two")
+		resp.Request = &http.Request{
+			Method: "POST",
+			URL:    &url.URL{Scheme: "https", Host: "test.io", Path: "/api"},
+			Header: http.Header{"Authorization": {"Bearer secret"}},
+		}
+
+		var buf bytes.Buffer
+		o := newTextOutputter(cfg.Output, newTemplate(cfg, logging.NamedLogger("test")), &buf)
+		err := o.Write(resp)
+		return buf.String(), err
+	}
+
+	DescribeTable("Apply",
+		func(template string, expect string) {
+			// Act
+			actual, err := apply(template, `{"id":"abc","count":12345678901234567890,"items":[{"n":1},{"n":2}],"empty":"","enc":"aGVsbG8="}`)
+
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actual).To(Equal(expect))
+		},
+		Entry("default template", "", `{"id":"abc","count":12345678901234567890,"items":[{"n":1},{"n":2}],"empty":"","enc":"aGVsbG8="}`),
+		Entry("status", "{{ .Status }} {{ .Response.Status }}", "200 200 OK"),
+		Entry("branch on status", "{{ if eq .Status 200 }}ok{{ else }}failed{{ end }}", "ok"),
+		Entry("request", "{{ .Request.Method }} {{ .Request.URL }} {{ index .Request.Headers.Authorization 0 }}", "POST https://test.io/api Bearer ********"),
+		Entry("loop over headers", "{{ range $k, $v := .Headers }}{{ $k }}={{ len $v }};{{ end }}", "Content-Type=1;X-Multi=2;"),
+		Entry("json fields", "{{ .JSON.id }} {{ .JSON.count }}", "abc 12345678901234567890"),
+		Entry("json loop", "{{ range .JSON.items }}{{ .n }},{{ end }}", "1,2,"),
+		Entry("json func", "{{ json .JSON.items }}", `[{"n":1},{"n":2}]`),
+		Entry("toPrettyJSON func", "{{ toPrettyJSON (index .JSON.items 0) }}", "{\n  \"n\": 1\n}"),
+		Entry("upper func", "{{ upper .JSON.id }}", "ABC"),
+		Entry("default func with empty value", `{{ .JSON.empty | default "none" }}`, "none"),
+		Entry("default func with missing value", `{{ .JSON.missing | default "none" }}`, "none"),
+		Entry("default func with value", `{{ .JSON.id | default "none" }}`, "abc"),
+		Entry("b64dec func", "{{ b64dec .JSON.enc }}", "hello"),
+		Entry("header func", `{{ header "x-multi" }}`, "one,two"),
+		Entry("properties", "{{ .Properties.env }}", "test"),
+		Entry("escapes are expanded", `{{ .Status }}\n`, "200\n"),
+		Entry("escapes in string literals are left to the parser", `{{ printf "%d\n" .Status }}{{ printf "%s\t%s" "a" "b" }}\n`, "200\na\tb\n"),
+	)
+
+	DescribeTable("errors",
+		func(template string) {
+			// Act
+			actual, err := apply(template, `{}`)
+
+			// Assert
+			Expect(err).To(MatchError(ErrInvalidTemplate))
+			Expect(actual).To(BeEmpty())
+		},
+		Entry("parse error", "{{ .Status "),
+		Entry("unknown function", "{{ nope .Status }}"),
+		Entry("execute error", "{{ b64dec \"!!\" }}"),
+	)
+})
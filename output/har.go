@@ -0,0 +1,288 @@
+package output
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	"github.com/keithpaterson/postal/config"
+	"github.com/keithpaterson/postal/redact"
+	"github.com/keithpaterson/postal/trace"
+)
+
+// harOutputter writes the exchange as an HTTP Archive (HAR 1.2), which can be opened in browser developer tools.
+// Every hop (i.e. each redirect) is a separate entry; sensitive request headers are masked.
+type harOutputter struct {
+	cfg    *config.Config
+	writer io.Writer
+}
+
+type harDocument struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	ServerIPAddress string      `json:"serverIPAddress,omitempty"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int64          `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int64          `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// timings that are not known are -1, as the HAR specification requires
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	SSL     float64 `json:"ssl"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+func newHarOutputter(cfg *config.Config, writer io.Writer) *harOutputter {
+	return &harOutputter{cfg: cfg, writer: writer}
+}
+
+func (o *harOutputter) Write(resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	t := trace.FromResponse(resp)
+	if t != nil {
+		t.MarkDone()
+	}
+
+	// earlier hops are redirects, whose bodies have already been discarded
+	hops := []trace.Hop{{Request: resp.Request, Response: resp}}
+	if t != nil {
+		if recorded := t.Hops(); len(recorded) > 0 {
+			hops = recorded
+			hops[len(hops)-1].Response = resp
+		}
+	}
+
+	doc := harDocument{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "postal", Version: harCreatorVersion()},
+		Entries: make([]harEntry, 0, len(hops)),
+	}}
+	for index, hop := range hops {
+		if hop.Response == nil {
+			continue
+		}
+		entry := o.entry(hop, t)
+		if index == len(hops)-1 {
+			entry.Response.Content = harBody(resp.Header.Get("content-type"), body)
+			entry.Response.BodySize = int64(len(body))
+			entry.Timings, entry.Time = harFinalTimings(t)
+			if t != nil {
+				entry.ServerIPAddress = hostOf(t.RemoteAddr())
+			}
+		}
+		doc.Log.Entries = append(doc.Log.Entries, entry)
+	}
+
+	encoder := json.NewEncoder(o.writer)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(doc)
+}
+
+func (o *harOutputter) entry(hop trace.Hop, t *trace.Trace) harEntry {
+	started := time.Now()
+	if t != nil {
+		started = t.Start
+	}
+	resp := hop.Response
+	redactor := redact.New(o.cfg)
+	return harEntry{
+		StartedDateTime: started.Format(time.RFC3339Nano),
+		Request:         o.request(hop.Request, resp.Proto),
+		Response: harResponse{
+			Status:      resp.StatusCode,
+			StatusText:  http.StatusText(resp.StatusCode),
+			HTTPVersion: resp.Proto,
+			Cookies:     harCookies(resp.Cookies(), redactor.IsSensitiveHeader("set-cookie")),
+			Headers:     harHeaders(redactor.Headers(resp.Header)),
+			Content:     harContent{MimeType: resp.Header.Get("content-type")},
+			RedirectURL: resp.Header.Get("location"),
+			HeadersSize: -1,
+		},
+		Timings: harTimings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1},
+	}
+}
+
+func (o *harOutputter) request(req *http.Request, proto string) harRequest {
+	if req == nil {
+		return harRequest{Cookies: []harNameValue{}, Headers: []harNameValue{}, QueryString: []harNameValue{}, HeadersSize: -1}
+	}
+	if proto == "" {
+		proto = req.Proto
+	}
+
+	redactor := redact.New(o.cfg)
+	result := harRequest{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		HTTPVersion: proto,
+		Cookies:     harCookies(req.Cookies(), redactor.IsSensitiveHeader("cookie")),
+		Headers:     harHeaders(redactor.Headers(req.Header)),
+		QueryString: harHeaders(http.Header(req.URL.Query())),
+		HeadersSize: -1,
+		BodySize:    max(req.ContentLength, 0),
+	}
+	result.PostData = harPostBody(req)
+	return result
+}
+
+func harPostBody(req *http.Request) *harPostData {
+	data, ok := readRequestBody(req)
+	if !ok {
+		return nil
+	}
+	postData := &harPostData{MimeType: req.Header.Get("content-type")}
+	if utf8.Valid(data) {
+		postData.Text = string(data)
+	} else {
+		// HAR has no encoding for post data; say what we did
+		postData.Text = base64.StdEncoding.EncodeToString(data)
+		postData.Comment = "base64"
+	}
+	return postData
+}
+
+func harBody(contentType string, body []byte) harContent {
+	content := harContent{Size: int64(len(body)), MimeType: contentType}
+	if utf8.Valid(body) {
+		content.Text = string(body)
+	} else {
+		content.Text = base64.StdEncoding.EncodeToString(body)
+		content.Encoding = "base64"
+	}
+	return content
+}
+
+// harFinalTimings describes the final response; lookups, connections and handshakes include those for any redirects.
+func harFinalTimings(t *trace.Trace) (harTimings, float64) {
+	if t == nil {
+		return harTimings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1}, 0
+	}
+	timings := t.Timings()
+	result := harTimings{
+		Blocked: -1,
+		DNS:     milliseconds(timings.DNS),
+		// in HAR, connect includes the TLS handshake
+		Connect: milliseconds(timings.Connect + timings.TLS),
+		SSL:     milliseconds(timings.TLS),
+		Wait:    milliseconds(timings.TTFB),
+		Receive: milliseconds(timings.Transfer),
+	}
+	return result, milliseconds(timings.Total)
+}
+
+// harHeaders flattens the headers into name/value pairs, sorted by name
+func harHeaders(headers http.Header) []harNameValue {
+	result := []harNameValue{}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		for _, value := range headers[name] {
+			result = append(result, harNameValue{Name: name, Value: value})
+		}
+	}
+	return result
+}
+
+// harCookies lists the cookies; their values are masked when the header that carries them is sensitive
+func harCookies(cookies []*http.Cookie, mask bool) []harNameValue {
+	result := []harNameValue{}
+	for _, cookie := range cookies {
+		value := cookie.Value
+		if mask && value != "" {
+			value = redact.Mask
+		}
+		result = append(result, harNameValue{Name: cookie.Name, Value: value})
+	}
+	return result
+}
+
+func harCreatorVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
+
+// hostOf removes the port from an ip:port address
+func hostOf(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
@@ -0,0 +1,124 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/keithpaterson/postal/config"
+	"github.com/keithpaterson/postal/trace"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HAR Output", func() {
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/start", func(w http.ResponseWriter, r *http.Request) {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "s3cr3t"})
+			http.Redirect(w, r, "/end?x=1", http.StatusTemporaryRedirect)
+		})
+		mux.HandleFunc("/end", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write([]byte{0xff, 0x00, 0x01})
+		})
+		server = httptest.NewTLSServer(mux)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("writes an entry for every hop", func() {
+		// Arrange
+		client := server.Client()
+		client.Transport = trace.NewTransport(client.Transport)
+		req, _ := http.NewRequest("POST", server.URL+"/start?q=a+b", strings.NewReader(`{"a":1}`))
+		req.Header.Set("Authorization", "Bearer secret")
+		req.Header.Set("Content-Type", "application/json")
+		req.AddCookie(&http.Cookie{Name: "token", Value: "abc"})
+
+		resp, err := client.Do(trace.WithTrace(req, trace.New()))
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+
+		var buf bytes.Buffer
+		o := newHarOutputter(config.NewConfig(), &buf)
+
+		// Act
+		err = o.Write(resp)
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		var doc harDocument
+		Expect(json.Unmarshal(buf.Bytes(), &doc)).To(Succeed())
+		Expect(doc.Log.Version).To(Equal("1.2"))
+		Expect(doc.Log.Creator.Name).To(Equal("postal"))
+		Expect(doc.Log.Entries).To(HaveLen(2))
+
+		first := doc.Log.Entries[0]
+		Expect(first.Request.Method).To(Equal("POST"))
+		Expect(first.Request.URL).To(Equal(server.URL + "/start?q=a+b"))
+		Expect(first.Request.QueryString).To(Equal([]harNameValue{{Name: "q", Value: "a b"}}))
+		Expect(first.Request.Headers).To(ContainElement(harNameValue{Name: "Authorization", Value: "Bearer ********"}))
+		Expect(first.Request.Headers).To(ContainElement(harNameValue{Name: "Cookie", Value: "********"}))
+		Expect(first.Request.Cookies).To(Equal([]harNameValue{{Name: "token", Value: "********"}}))
+		Expect(first.Response.Headers).To(ContainElement(harNameValue{Name: "Set-Cookie", Value: "********"}))
+		Expect(first.Response.Cookies).To(Equal([]harNameValue{{Name: "session", Value: "********"}}))
+		Expect(buf.String()).ToNot(ContainSubstring("s3cr3t"))
+		Expect(buf.String()).ToNot(ContainSubstring("abc"))
+		Expect(first.Request.PostData).To(Equal(&harPostData{MimeType: "application/json", Text: `{"a":1}`}))
+		Expect(first.Response.Status).To(Equal(307))
+		Expect(first.Response.RedirectURL).To(Equal("/end?x=1"))
+		Expect(first.Timings.DNS).To(BeEquivalentTo(-1))
+
+		last := doc.Log.Entries[1]
+		Expect(last.Request.URL).To(Equal(server.URL + "/end?x=1"))
+		Expect(last.Response.Status).To(Equal(200))
+		Expect(last.Response.HTTPVersion).To(Equal("HTTP/1.1"))
+		Expect(last.Response.Content).To(Equal(harContent{Size: 3, MimeType: "application/octet-stream", Text: "/wAB", Encoding: "base64"}))
+		Expect(last.Time).To(BeNumerically(">", 0))
+		Expect(last.Timings.SSL).To(BeNumerically(">", 0))
+		Expect(last.ServerIPAddress).To(Equal("127.0.0.1"))
+	})
+
+	It("writes the final exchange without a trace", func() {
+		// Arrange
+		var buf bytes.Buffer
+		o := newHarOutputter(config.NewConfig(), &buf)
+
+		// Act
+		err := o.Write(newTestResponse("application/json", `{"id":1}`))
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		var doc harDocument
+		Expect(json.Unmarshal(buf.Bytes(), &doc)).To(Succeed())
+		Expect(doc.Log.Entries).To(HaveLen(1))
+		Expect(doc.Log.Entries[0].Response.Content).To(Equal(harContent{Size: 8, MimeType: "application/json", Text: `{"id":1}`}))
+	})
+
+	It("writes cookies when secrets are shown", func() {
+		// Arrange
+		cfg := config.NewConfig()
+		cfg.Runtime.ShowSecrets = true
+		resp := newTestResponse("application/json", `{}`)
+		resp.Header.Set("Set-Cookie", "session=s3cr3t")
+		var buf bytes.Buffer
+		o := newHarOutputter(cfg, &buf)
+
+		// Act
+		err := o.Write(resp)
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		var doc harDocument
+		Expect(json.Unmarshal(buf.Bytes(), &doc)).To(Succeed())
+		Expect(doc.Log.Entries[0].Response.Cookies).To(Equal([]harNameValue{{Name: "session", Value: "s3cr3t"}}))
+	})
+})
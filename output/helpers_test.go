@@ -0,0 +1,24 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+// newTestResponse returns a "200 OK" response with the body (and content type, unless it is empty);
+// tests that need more (e.g. the request) add it to the result.
+func newTestResponse(contentType string, body string) *http.Response {
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    200,
+		Proto:         "HTTP/1.1",
+		Header:        header,
+		ContentLength: int64(len(body)),
+		Body:          io.NopCloser(bytes.NewBufferString(body)),
+	}
+}
@@ -0,0 +1,138 @@
+package output
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/keithpaterson/postal/config"
+	"github.com/keithpaterson/postal/redact"
+	"github.com/keithpaterson/postal/trace"
+)
+
+// jsonOutputter writes the whole exchange as a single JSON document, for use by scripts.
+type jsonOutputter struct {
+	cfg    *config.Config
+	writer io.Writer
+}
+
+type jsonDocument struct {
+	Request  jsonRequest  `json:"request"`
+	Response jsonResponse `json:"response"`
+	Timings  *jsonTimings `json:"timings,omitempty"`
+}
+
+type jsonRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers"`
+}
+
+type jsonResponse struct {
+	Status        string      `json:"status"`
+	StatusCode    int         `json:"status_code"`
+	Proto         string      `json:"proto"`
+	Headers       http.Header `json:"headers"`
+	ContentLength int64       `json:"content_length"`
+	// one of "json", "text" or "base64"
+	BodyEncoding string `json:"body_encoding"`
+	Body         any    `json:"body"`
+}
+
+type jsonTimings struct {
+	Start      time.Time `json:"start"`
+	DNSMs      float64   `json:"dns_ms"`
+	ConnectMs  float64   `json:"connect_ms"`
+	TLSMs      float64   `json:"tls_ms"`
+	TTFBMs     float64   `json:"ttfb_ms"`
+	TransferMs float64   `json:"transfer_ms"`
+	ResponseMs float64   `json:"response_ms"`
+	TotalMs    float64   `json:"total_ms"`
+}
+
+func newJsonOutputter(cfg *config.Config, writer io.Writer) *jsonOutputter {
+	return &jsonOutputter{cfg: cfg, writer: writer}
+}
+
+func (o *jsonOutputter) Write(resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	t := trace.FromResponse(resp)
+	if t != nil {
+		t.MarkDone()
+	}
+
+	doc := jsonDocument{
+		Request: o.request(resp.Request),
+		Response: jsonResponse{
+			Status:        resp.Status,
+			StatusCode:    resp.StatusCode,
+			Proto:         resp.Proto,
+			Headers:       nonNilHeader(resp.Header),
+			ContentLength: resp.ContentLength,
+		},
+		Timings: o.timings(t),
+	}
+	doc.Response.BodyEncoding, doc.Response.Body = jsonBody(resp.Header.Get("content-type"), body)
+
+	encoder := json.NewEncoder(o.writer)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(doc)
+}
+
+func (o *jsonOutputter) request(req *http.Request) jsonRequest {
+	if req == nil {
+		return jsonRequest{Headers: http.Header{}}
+	}
+	return jsonRequest{
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Headers: nonNilHeader(redact.New(o.cfg).Headers(req.Header)),
+	}
+}
+
+func (o *jsonOutputter) timings(t *trace.Trace) *jsonTimings {
+	if t == nil {
+		return nil
+	}
+	timings := t.Timings()
+	return &jsonTimings{
+		Start:      t.Start,
+		DNSMs:      milliseconds(timings.DNS),
+		ConnectMs:  milliseconds(timings.Connect),
+		TLSMs:      milliseconds(timings.TLS),
+		TTFBMs:     milliseconds(timings.TTFB),
+		TransferMs: milliseconds(timings.Transfer),
+		ResponseMs: milliseconds(timings.Response),
+		TotalMs:    milliseconds(timings.Total),
+	}
+}
+
+// jsonBody embeds JSON content as-is; anything else is a string, or base64 when it is not valid UTF-8
+func jsonBody(contentType string, body []byte) (string, any) {
+	if contentKindOf(contentType) == contentJson && json.Valid(body) {
+		return "json", json.RawMessage(body)
+	}
+	if utf8.Valid(body) {
+		return "text", string(body)
+	}
+	return "base64", base64.StdEncoding.EncodeToString(body)
+}
+
+// an empty multi-map is easier to consume than null
+func nonNilHeader(headers http.Header) http.Header {
+	if headers == nil {
+		return http.Header{}
+	}
+	return headers
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,83 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/keithpaterson/postal/config"
+	"github.com/keithpaterson/postal/trace"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// adds a traced request and a multi-value header to a test response
+func withTestRequest(resp *http.Response) *http.Response {
+	req := &http.Request{
+		Method: "GET",
+		URL:    &url.URL{Scheme: "http", Host: "localhost", Path: "/get"},
+		Header: http.Header{"Authorization": {"Bearer abc"}, "Accept": {"*/*"}},
+	}
+	resp.Request = trace.WithTrace(req, trace.New())
+	resp.Header["X-Multi"] = []string{"one", "two"}
+	return resp
+}
+
+var _ = Describe("JSON Output", func() {
+	DescribeTable("Write",
+		func(contentType string, body []byte, expectEncoding string, expectBody any) {
+			// Arrange
+			var buf bytes.Buffer
+			o := newJsonOutputter(config.NewConfig(), &buf)
+
+			// Act
+			err := o.Write(withTestRequest(newTestResponse(contentType, string(body))))
+
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			var doc map[string]any
+			Expect(json.Unmarshal(buf.Bytes(), &doc)).To(Succeed())
+
+			Expect(doc["request"]).To(Equal(map[string]any{
+				"method":  "GET",
+				"url":     "http://localhost/get",
+				"headers": map[string]any{"Authorization": []any{"Bearer ********"}, "Accept": []any{"*/*"}},
+			}))
+
+			resp := doc["response"].(map[string]any)
+			Expect(resp["status"]).To(Equal("200 OK"))
+			Expect(resp["status_code"]).To(BeEquivalentTo(200))
+			Expect(resp["proto"]).To(Equal("HTTP/1.1"))
+			Expect(resp["headers"]).To(HaveKeyWithValue("X-Multi", []any{"one", "two"}))
+			Expect(resp["body_encoding"]).To(Equal(expectEncoding))
+			Expect(resp["body"]).To(Equal(expectBody))
+
+			Expect(doc["timings"]).To(HaveKey("start"))
+			Expect(doc["timings"]).To(HaveKey("total_ms"))
+		},
+		Entry("json body", "application/json", []byte(`{"a":1}`), "json", map[string]any{"a": float64(1)}),
+		Entry("json suffix", "application/problem+json; charset=utf-8", []byte(`[1,2]`), "json", []any{float64(1), float64(2)}),
+		Entry("invalid json", "application/json", []byte(`{oops`), "text", "{oops"),
+		Entry("text body", "text/plain", []byte("hello"), "text", "hello"),
+		Entry("binary body", "application/octet-stream", []byte{0xff, 0xfe, 0x00}, "base64", "//4A"),
+	)
+
+	It("omits timings when the exchange was not traced", func() {
+		// Arrange
+		var buf bytes.Buffer
+		o := newJsonOutputter(config.NewConfig(), &buf)
+		resp := withTestRequest(newTestResponse("text/plain", "hello"))
+		resp.Request = nil
+
+		// Act
+		err := o.Write(resp)
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buf.String()).ToNot(ContainSubstring("timings"))
+		Expect(strings.HasSuffix(buf.String(), "}\n")).To(BeTrue())
+	})
+})
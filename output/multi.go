@@ -0,0 +1,54 @@
+package output
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/keithpaterson/postal/trace"
+)
+
+// multiOutputter writes the same response to several outputters (i.e. the configured sinks).
+type multiOutputter struct {
+	outputters []Outputter
+}
+
+func newMultiOutputter(outputters []Outputter) *multiOutputter {
+	return &multiOutputter{outputters: outputters}
+}
+
+// Write spools the body into a temporary file so that every outputter can read it, without holding it
+// in memory (e.g. for binary output); all the outputters are written even if one of them fails, and
+// any errors are combined.
+func (o *multiOutputter) Write(resp *http.Response) error {
+	spool, err := os.CreateTemp("", "postal-body-*")
+	if err != nil {
+		resp.Body.Close()
+		return err
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	_, err = io.Copy(spool, resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	if t := trace.FromResponse(resp); t != nil {
+		t.MarkDone()
+	}
+
+	var errs []error
+	for _, outputter := range o.outputters {
+		if _, err = spool.Seek(0, io.SeekStart); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		sinkResp := *resp
+		sinkResp.Body = io.NopCloser(spool)
+		if err = outputter.Write(&sinkResp); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,71 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/keithpaterson/postal/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sinks", func() {
+	It("writes the response to every sink", func() {
+		// Arrange
+		dir := GinkgoT().TempDir()
+		cfg := config.NewConfig()
+		cfg.Output.Sinks = []config.SinkConfig{
+			{Format: "raw", Filename: filepath.Join(dir, "out", "resp.bin")},
+			{Template: "${response:status-code} ${response:json=id}\n", Filename: filepath.Join(dir, "runs.log"), Append: true},
+		}
+
+		// Act
+		err1 := NewOutputter(cfg).Write(newTestResponse("application/json", `{"id":"a"}`))
+		err2 := NewOutputter(cfg).Write(newTestResponse("application/json", `{"id":"b"}`))
+
+		// Assert
+		Expect(err1).ToNot(HaveOccurred())
+		Expect(err2).ToNot(HaveOccurred())
+		Expect(os.ReadFile(filepath.Join(dir, "out", "resp.bin"))).To(BeEquivalentTo(`{"id":"b"}`))
+		Expect(os.ReadFile(filepath.Join(dir, "runs.log"))).To(BeEquivalentTo("200 a\n200 b\n"))
+	})
+
+	It("writes every sink even if one fails", func() {
+		// Arrange
+		dir := GinkgoT().TempDir()
+		cfg := config.NewConfig()
+		cfg.Output.Sinks = []config.SinkConfig{
+			{Template: "${response:json=missing}", Filename: filepath.Join(dir, "missing.txt")},
+			{Format: "raw", Filename: filepath.Join(dir, "resp.bin")},
+		}
+
+		// Act
+		err := NewOutputter(cfg).Write(newTestResponse("application/json", `{"id":"a"}`))
+
+		// Assert
+		Expect(err).To(MatchError(ErrResolveFailed))
+		Expect(os.ReadFile(filepath.Join(dir, "resp.bin"))).To(BeEquivalentTo(`{"id":"a"}`))
+	})
+	It("streams the body to every sink through a temporary file", func() {
+		// Arrange
+		dir := GinkgoT().TempDir()
+		spoolDir := GinkgoT().TempDir()
+		GinkgoT().Setenv("TMPDIR", spoolDir)
+		body := string([]byte{0xff, 0x00, 0xfe, 'p', 'o', 's', 't', 'a', 'l'})
+		cfg := config.NewConfig()
+		cfg.Output.Sinks = []config.SinkConfig{
+			{Format: "binary", Filename: filepath.Join(dir, "one.bin")},
+			{Format: "binary", Filename: filepath.Join(dir, "two.bin")},
+		}
+
+		// Act
+		err := NewOutputter(cfg).Write(newTestResponse("application/octet-stream", body))
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.ReadFile(filepath.Join(dir, "one.bin"))).To(BeEquivalentTo(body))
+		Expect(os.ReadFile(filepath.Join(dir, "two.bin"))).To(BeEquivalentTo(body))
+		Expect(os.ReadDir(spoolDir)).To(BeEmpty())
+	})
+})
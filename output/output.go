@@ -8,41 +8,76 @@ import (
 	"github.com/keithpaterson/postal/config"
 	"github.com/keithpaterson/postal/logging"
 
-	ulog "github.com/keithpaterson/resweave-utils/logging"
+	"go.uber.org/zap"
 )
 
 type Outputter interface {
 	Write(response *http.Response) error
 }
 
+// NewOutputter creates the outputter for the [output] section, or for each of its sinks when any are configured.
 func NewOutputter(cfg *config.Config) Outputter {
 	log := logging.NamedLogger("output")
 
-	writer, err := openWriter(cfg.Output)
-	if err != nil {
-		log.Errorw("NewOutputter", ulog.LogKeyError, err)
+	if len(cfg.Output.Sinks) == 0 {
+		return newOutputter(cfg, log)
 	}
 
+	outputters := make([]Outputter, 0, len(cfg.Output.Sinks))
+	for _, sink := range cfg.Output.Sinks {
+		sinkCfg := *cfg
+		sinkCfg.Output = cfg.Output.ForSink(sink)
+		outputters = append(outputters, newOutputter(&sinkCfg, log))
+	}
+	return newMultiOutputter(outputters)
+}
+
+func newOutputter(cfg *config.Config, log *zap.SugaredLogger) Outputter {
 	template := newTemplate(cfg, log)
 
+	var writer io.Writer
+	var file *fileWriter
+	switch cfg.Output.Filename {
+	case "", "stdout":
+		writer = os.Stdout
+	case "stderr":
+		writer = os.Stderr
+	default:
+		file = newFileWriter(cfg.Output, template)
+		writer = file
+	}
+
+	color := newColorizer(cfg.Output, writer)
+	formatter := newBodyFormatter(cfg.Output, color)
+	template.withFormatter(formatter)
+
+	outputter := newFormatOutputter(cfg, log, template, formatter, writer)
+	if file != nil {
+		return newFileOutputter(outputter, file)
+	}
+	return outputter
+}
+
+func newFormatOutputter(cfg *config.Config, log *zap.SugaredLogger, template *template, formatter *bodyFormatter, writer io.Writer) Outputter {
 	switch cfg.Output.OutFormat() {
 	case config.OutFmtRaw:
 		return newRawOutputter(cfg.Output, writer)
 	case config.OutFmtText:
 		return newTextOutputter(cfg.Output, template, writer)
+	case config.OutFmtJson:
+		return newJsonOutputter(cfg, writer)
+	case config.OutFmtVerbose:
+		return newVerboseOutputter(cfg, writer).withFormatter(formatter)
+	case config.OutFmtBase64:
+		return newBase64Outputter(cfg.Output, writer)
+	case config.OutFmtHex:
+		return newHexOutputter(cfg.Output, writer)
+	case config.OutFmtBinary:
+		return newBinaryOutputter(cfg.Output, writer)
+	case config.OutFmtHar:
+		return newHarOutputter(cfg, writer)
 	default:
 		log.Warnw("NewOutputter", "warning", "unsupported outputter", "outputter", cfg.Output.Format)
 		return newTextOutputter(cfg.Output, template, writer)
 	}
 }
-
-func openWriter(cfg config.OutputConfig) (io.Writer, error) {
-	switch cfg.Filename {
-	case "", "stdout":
-		return os.Stdout, nil
-	case "stderr":
-		return os.Stderr, nil
-	default:
-		return os.Create(cfg.Filename)
-	}
-}
@@ -22,8 +22,27 @@ var _ = Describe("Output", func() {
 		},
 		Entry("valid raw", "raw", &rawOutputter{}),
 		Entry("valid text", "text", &textOutputter{}),
+		Entry("valid json", "json", &jsonOutputter{}),
+		Entry("valid verbose", "verbose", &verboseOutputter{}),
+		Entry("valid base64", "base64", &base64Outputter{}),
+		Entry("valid hex", "hex", &hexOutputter{}),
+		Entry("valid binary", "binary", &binaryOutputter{}),
+		Entry("valid har", "har", &harOutputter{}),
 		Entry("invalid foo", "foo", &textOutputter{}),
 		Entry("invalid bar", "bar", &textOutputter{}),
 		Entry("invalid empty", "", &textOutputter{}),
 	)
+
+	It("creates an outputter for each sink", func() {
+		// Arrange
+		cfg := config.NewConfig()
+		cfg.Output.Sinks = []config.SinkConfig{{Format: "raw", Filename: "stderr"}, {Filename: "stdout"}}
+
+		// Act
+		outputter := NewOutputter(cfg)
+
+		// Assert
+		Expect(outputter).To(BeAssignableToTypeOf(&multiOutputter{}))
+		Expect(outputter.(*multiOutputter).outputters).To(HaveExactElements(BeAssignableToTypeOf(&rawOutputter{}), BeAssignableToTypeOf(&textOutputter{})))
+	})
 })
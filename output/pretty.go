@@ -0,0 +1,134 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"mime"
+	"strings"
+
+	"github.com/keithpaterson/postal/config"
+)
+
+const (
+	prettyIndent = "  "
+)
+
+type contentKind int
+
+const (
+	contentOther contentKind = iota
+	contentJson
+	contentXml
+)
+
+func contentKindOf(contentType string) contentKind {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentOther
+	}
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return contentJson
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return contentXml
+	default:
+		return contentOther
+	}
+}
+
+// prettyJson indents the JSON body; bodies that aren't valid JSON are returned as-is
+func prettyJson(body []byte) []byte {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, body, "", prettyIndent); err != nil {
+		return body
+	}
+	return buf.Bytes()
+}
+
+// prettyXml indents the XML body; bodies that aren't valid XML are returned as-is
+//
+// Raw tokens are used so that namespace prefixes are written exactly as they were received.
+func prettyXml(body []byte) []byte {
+	var buf bytes.Buffer
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", prettyIndent)
+
+	for {
+		token, err := decoder.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return body
+		}
+
+		switch t := token.(type) {
+		case xml.CharData:
+			// the encoder adds its own whitespace between elements
+			if len(bytes.TrimSpace(t)) == 0 {
+				continue
+			}
+		case xml.StartElement:
+			t.Name = rawName(t.Name)
+			for index := range t.Attr {
+				t.Attr[index].Name = rawName(t.Attr[index].Name)
+			}
+			token = t
+		case xml.EndElement:
+			t.Name = rawName(t.Name)
+			token = t
+		}
+		if err = encoder.EncodeToken(token); err != nil {
+			return body
+		}
+	}
+	if err := encoder.Flush(); err != nil {
+		return body
+	}
+	return buf.Bytes()
+}
+
+func rawName(name xml.Name) xml.Name {
+	if name.Space == "" {
+		return name
+	}
+	return xml.Name{Local: name.Space + ":" + name.Local}
+}
+
+// bodyFormatter applies the pretty and color options to a response body
+type bodyFormatter struct {
+	pretty bool
+	color  *colorizer
+}
+
+func newBodyFormatter(cfg config.OutputConfig, color *colorizer) *bodyFormatter {
+	return &bodyFormatter{pretty: cfg.Pretty, color: color}
+}
+
+// active is true when Format might change the body
+func (f *bodyFormatter) active() bool {
+	return f != nil && (f.pretty || (f.color != nil && f.color.enabled))
+}
+
+func (f *bodyFormatter) Format(contentType string, body []byte) []byte {
+	if !f.active() {
+		return body
+	}
+
+	kind := contentKindOf(contentType)
+	if f.pretty {
+		switch kind {
+		case contentJson:
+			body = prettyJson(body)
+		case contentXml:
+			body = prettyXml(body)
+		}
+	}
+	if kind == contentJson && json.Valid(body) {
+		body = f.color.Json(body)
+	}
+	return body
+}
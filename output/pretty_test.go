@@ -0,0 +1,88 @@
+package output
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/keithpaterson/postal/config"
+	"github.com/keithpaterson/postal/logging"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pretty", func() {
+	DescribeTable("bodyFormatter",
+		func(pretty bool, color bool, contentType string, body string, expect string) {
+			// Arrange
+			f := &bodyFormatter{pretty: pretty, color: &colorizer{enabled: color}}
+
+			// Act
+			actual := f.Format(contentType, []byte(body))
+
+			// Assert
+			Expect(string(actual)).To(Equal(expect))
+		},
+		Entry("json", true, false, "application/json", `{"a":[1,true]}`, "{\n  \"a\": [\n    1,\n    true\n  ]\n}"),
+		Entry("json suffix", true, false, "application/vnd.api+json; charset=utf-8", `{"a":1}`, "{\n  \"a\": 1\n}"),
+		Entry("invalid json", true, false, "application/json", `{"a":`, `{"a":`),
+		Entry("xml", true, false, "text/xml", `<a x="1"><b>text</b><c/></a>`, "<a x=\"1\">\n  <b>text</b>\n  <c></c>\n</a>"),
+		Entry("xml keeps prefixes", true, false, "application/soap+xml", `<s:Envelope xmlns:s="urn:s"><s:Body>1</s:Body></s:Envelope>`, "<s:Envelope xmlns:s=\"urn:s\">\n  <s:Body>1</s:Body>\n</s:Envelope>"),
+		Entry("invalid xml", true, false, "application/xml", `<a><b></a>`, `<a><b></a>`),
+		Entry("other content", true, false, "text/plain", `{"a":1}`, `{"a":1}`),
+		Entry("not pretty", false, false, "application/json", `{"a":1}`, `{"a":1}`),
+		Entry("color", false, true, "application/json", `{"a":"b","n":-1.5,"t":null}`,
+			"{"+ansiBold+ansiBlue+`"a"`+ansiReset+":"+ansiGreen+`"b"`+ansiReset+","+
+				ansiBold+ansiBlue+`"n"`+ansiReset+":"+ansiCyan+"-1.5"+ansiReset+","+
+				ansiBold+ansiBlue+`"t"`+ansiReset+":"+ansiMagenta+"null"+ansiReset+"}"),
+		Entry("color escaped quotes", false, true, "application/json", `["a\"b"]`, "["+ansiGreen+`"a\"b"`+ansiReset+"]"),
+		Entry("color ignores other content", false, true, "text/plain", `{"a":1}`, `{"a":1}`),
+	)
+
+	DescribeTable("colorizer",
+		func(mode string, noColor bool, expect bool) {
+			// Arrange
+			if noColor {
+				os.Setenv("NO_COLOR", "1")
+				defer os.Unsetenv("NO_COLOR")
+			}
+
+			// Act
+			c := newColorizer(config.OutputConfig{Color: mode}, &bytes.Buffer{})
+
+			// Assert
+			Expect(c.enabled).To(Equal(expect))
+		},
+		Entry("auto is off for non-terminals", "auto", false, false),
+		Entry("always", "always", false, true),
+		Entry("always ignores NO_COLOR", "always", true, true),
+		Entry("never", "never", false, false),
+	)
+
+	It("only colors terminals", func() {
+		// Arrange
+		file, err := os.CreateTemp(GinkgoT().TempDir(), "out")
+		Expect(err).ToNot(HaveOccurred())
+		defer file.Close()
+
+		// Act & Assert
+		Expect(isTerminal(file)).To(BeFalse())
+		Expect(isTerminal(&bytes.Buffer{})).To(BeFalse())
+	})
+
+	It("formats the text body token", func() {
+		// Arrange
+		cfg := config.NewConfig()
+		cfg.Output.Pretty = true
+		cfg.Output.Template = "${response:json=a} ${response:body}"
+		var buf bytes.Buffer
+		o := newTextOutputter(cfg.Output, newTemplate(cfg, logging.NamedLogger("test")).withFormatter(newBodyFormatter(cfg.Output, nil)), &buf)
+
+		// Act
+		err := o.Write(newTestResponse("application/json", `{"a":1}`))
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buf.String()).To(Equal("1 {\n  \"a\": 1\n}"))
+	})
+})
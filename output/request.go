@@ -0,0 +1,47 @@
+package output
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/keithpaterson/go-tools/resolver"
+	"github.com/keithpaterson/postal/redact"
+	postalresolver "github.com/keithpaterson/postal/resolver"
+	"github.com/keithpaterson/postal/trace"
+)
+
+// readRequestBody reads a copy of the request body, which http.NewRequest makes available via GetBody
+func readRequestBody(req *http.Request) ([]byte, bool) {
+	if req.GetBody == nil || req.ContentLength == 0 {
+		return nil, false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// requestAttempt is how many times the request was sent (from 1)
+func requestAttempt(resp *http.Response) int {
+	if t := trace.FromResponse(resp); t != nil {
+		return max(t.Attempts(), 1)
+	}
+	return 1
+}
+
+// newRequestResolver describes the final request (i.e. after redirects) for "${request:...}" tokens;
+// sensitive headers are masked.
+func (t *template) newRequestResolver(resp *http.Response) resolver.Resolver {
+	req := resp.Request
+	body, _ := readRequestBody(req)
+	masked := *req
+	masked.Header = redact.New(t.cfg).Headers(req.Header)
+	return postalresolver.NewRequestResolver(&masked, body).WithAttempt(requestAttempt(resp))
+}
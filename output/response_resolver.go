@@ -1,22 +1,37 @@
 package output
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/keithpaterson/go-tools/resolver"
+	"github.com/keithpaterson/postal/jsonpath"
+	"github.com/keithpaterson/postal/markup"
+	"github.com/keithpaterson/postal/trace"
 	"github.com/keithpaterson/resweave-utils/logging"
 	"go.uber.org/zap"
 )
 
+var (
+	ErrResolveFailed = errors.New("failed to resolve token")
+	ErrNoTimings     = errors.New("timings are not available")
+	ErrUnknownTiming = errors.New("unknown timing")
+)
+
 type responseResolver struct {
 	resolver.ResolverImpl
 
-	log  *zap.SugaredLogger
-	resp *http.Response
+	log        *zap.SugaredLogger
+	resp       *http.Response
+	namespaces map[string]string
+	formatter  *bodyFormatter
+	errs       []error
 
 	// store and cache (some) values
 	body *cached
@@ -31,6 +46,18 @@ func newResponseResolver(resp *http.Response, log *zap.SugaredLogger) *responseR
 	return &responseResolver{log: log.Named("response resolver"), resp: resp, body: &cached{cached: false}}
 }
 
+// WithFormatter sets the formatter used for the body token; the body is written as-is if it is nil
+func (r *responseResolver) WithFormatter(formatter *bodyFormatter) *responseResolver {
+	r.formatter = formatter
+	return r
+}
+
+// WithNamespaces sets the namespace prefixes that can be used in xpath expressions
+func (r *responseResolver) WithNamespaces(namespaces map[string]string) *responseResolver {
+	r.namespaces = namespaces
+	return r
+}
+
 func (r *responseResolver) Resolve(name string, token string) (string, bool) {
 	if name != "response" {
 		return token, false
@@ -49,7 +76,7 @@ func (r *responseResolver) resolveToken(token string) (string, bool) {
 			r.log.Errorw("resolveToken", "token", "body", logging.LogKeyError, err)
 			return token, false
 		}
-		value = string(body)
+		value = string(r.formatter.Format(r.resp.Header.Get("content-type"), body))
 	case "headers":
 		value = r.getAllHeaders(r.resp.Header)
 	case "status":
@@ -58,6 +85,8 @@ func (r *responseResolver) resolveToken(token string) (string, bool) {
 		value = strconv.Itoa(r.resp.StatusCode)
 	case "content-length", "contentlength":
 		value = strconv.FormatInt(r.resp.ContentLength, 10)
+	case "remote-addr":
+		value = r.getRemoteAddr()
 	}
 
 	// header is a special case..
@@ -70,9 +99,127 @@ func (r *responseResolver) resolveToken(token string) (string, bool) {
 		value = r.getHeader(name)
 	}
 
+	// as are body queries
+	if kind, expression, ok := strings.Cut(token, "="); ok {
+		switch kind {
+		case "json", "json-raw":
+			value, err = r.getJsonValue(expression, kind == "json-raw")
+		case "xpath":
+			value, err = r.getXPathValue(expression)
+		case "css":
+			value, err = r.getCSSValue(expression)
+		case "timing":
+			value, err = r.getTiming(expression)
+		case "tls":
+			value, err = r.getTLSDetail(expression)
+		}
+		if err != nil {
+			r.log.Errorw("resolveToken", "token", token, logging.LogKeyError, err)
+			r.errs = append(r.errs, fmt.Errorf("%w: ${response:%s}: %w", ErrResolveFailed, token, err))
+			return token, false
+		}
+	}
+
 	return value, true
 }
 
+func (r *responseResolver) getJsonValue(path string, raw bool) (string, error) {
+	body, err := r.getBody()
+	if err != nil {
+		return "", err
+	}
+	value, err := jsonpath.Lookup(body, path)
+	if err != nil {
+		return "", err
+	}
+	if raw {
+		return jsonpath.Raw(value)
+	}
+	return jsonpath.String(value)
+}
+
+func (r *responseResolver) getXPathValue(expression string) (string, error) {
+	body, err := r.getBody()
+	if err != nil {
+		return "", err
+	}
+	return markup.XPath(body, r.isHTML(), expression, r.namespaces)
+}
+
+func (r *responseResolver) getCSSValue(selector string) (string, error) {
+	body, err := r.getBody()
+	if err != nil {
+		return "", err
+	}
+	return markup.CSS(body, selector)
+}
+
+// getTiming returns the named duration in milliseconds.
+// The body is read first so that the transfer and total times are complete.
+func (r *responseResolver) getTiming(name string) (string, error) {
+	t := trace.FromResponse(r.resp)
+	if t == nil {
+		return "", ErrNoTimings
+	}
+	if _, err := r.getBody(); err != nil {
+		return "", err
+	}
+	t.MarkDone()
+
+	timings := t.Timings()
+	var d time.Duration
+	switch strings.ToLower(name) {
+	case "dns":
+		d = timings.DNS
+	case "connect":
+		d = timings.Connect
+	case "tls":
+		d = timings.TLS
+	case "ttfb":
+		d = timings.TTFB
+	case "transfer":
+		d = timings.Transfer
+	case "response":
+		d = timings.Response
+	case "total":
+		d = timings.Total
+	default:
+		return "", fmt.Errorf("%w: '%s'", ErrUnknownTiming, name)
+	}
+	return strconv.FormatFloat(milliseconds(d), 'f', 3, 64), nil
+}
+
+func (r *responseResolver) getTLSDetail(name string) (string, error) {
+	if strings.ToLower(name) == "remote-addr" {
+		return r.getRemoteAddr(), nil
+	}
+	return tlsDetail(r.resp.TLS, name)
+}
+
+func (r *responseResolver) getRemoteAddr() string {
+	if t := trace.FromResponse(r.resp); t != nil {
+		return t.RemoteAddr()
+	}
+	return ""
+}
+
+func (r *responseResolver) isHTML() bool {
+	mediaType, _, _ := mime.ParseMediaType(r.resp.Header.Get("content-type"))
+	return mediaType == "text/html"
+}
+
+// ignoringErrors runs resolve, discarding any errors that it records
+func (r *responseResolver) ignoringErrors(resolve func() string) string {
+	count := len(r.errs)
+	defer func() { r.errs = r.errs[:count] }()
+	return resolve()
+}
+
+// Err returns the errors for any tokens that could not be resolved
+func (r *responseResolver) Err() error {
+	return errors.Join(r.errs...)
+}
+
 func (r *responseResolver) getBody() ([]byte, error) {
 	if r.body.hasValue() {
 		return r.body.getBytes(), nil
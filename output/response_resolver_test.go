@@ -0,0 +1,177 @@
+package output
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/keithpaterson/postal/config"
+	"github.com/keithpaterson/postal/jsonpath"
+	"github.com/keithpaterson/postal/logging"
+	"github.com/keithpaterson/postal/trace"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Response Resolver", func() {
+	DescribeTable("json tokens",
+		func(template string, expect string) {
+			// Arrange
+			cfg := config.NewConfig()
+			cfg.Output.Template = template
+			var buf bytes.Buffer
+			o := newTextOutputter(cfg.Output, newTemplate(cfg, logging.NamedLogger("test")), &buf)
+
+			// Act
+			err := o.Write(newTestResponse("application/json", `{"id":"abc","data":{"items":[{"id":1},{"id":2}]}}`))
+
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(buf.String()).To(Equal(expect))
+		},
+		Entry("scalar", "id=${response:json=id}", "id=abc"),
+		Entry("raw", "id=${response:json-raw=id}", `id="abc"`),
+		Entry("index", "${response:json=data.items[1].id}", "2"),
+		Entry("wildcard", "${response:json=data.items[*].id}", "[1,2]"),
+		Entry("length", "${response:json=data.items.length()}", "2"),
+		Entry("multiple tokens read the body once", "${response:json=id} ${response:json=data.items[0].id} ${response:body}", `abc 1 {"id":"abc","data":{"items":[{"id":1},{"id":2}]}}`),
+	)
+
+	DescribeTable("markup tokens",
+		func(contentType string, body string, template string, expect string) {
+			// Arrange
+			cfg := config.NewConfig()
+			cfg.Output.Template = template
+			cfg.Output.Namespaces = map[string]string{"m": "urn:example"}
+			resp := newTestResponse("application/json", body)
+			resp.Header.Set("Content-Type", contentType)
+			var buf bytes.Buffer
+			o := newTextOutputter(cfg.Output, newTemplate(cfg, logging.NamedLogger("test")), &buf)
+
+			// Act
+			err := o.Write(resp)
+
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(buf.String()).To(Equal(expect))
+		},
+		Entry("xpath", "text/xml", `<Envelope><Body><Result>ok</Result></Body></Envelope>`, "${response:xpath=//Envelope/Body/Result/text()}", "ok"),
+		Entry("xpath with namespace", "application/soap+xml", `<r xmlns="urn:example"><v>1</v></r>`, "${response:xpath=/m:r/m:v}", "1"),
+		Entry("xpath html", "text/html; charset=utf-8", `<html><body><p>unclosed</body></html>`, "${response:xpath=//p}", "unclosed"),
+		Entry("css", "text/html", `<html><head><title>Hello</title></head></html>`, "${response:css=title}", "Hello"),
+		Entry("multiple tokens", "text/html", `<html><head><title>Hello</title></head><body><h1>There</h1></body></html>`, "${response:css=title} ${response:xpath=//h1}", "Hello There"),
+	)
+
+	DescribeTable("timing tokens",
+		func(template string, traced bool, expect error) {
+			// Arrange
+			cfg := config.NewConfig()
+			cfg.Output.Template = template
+			resp := newTestResponse("application/json", `{}`)
+			if traced {
+				req, _ := http.NewRequest("GET", "http://localhost", nil)
+				resp.Request = trace.WithTrace(req, trace.New())
+			}
+			var buf bytes.Buffer
+			o := newTextOutputter(cfg.Output, newTemplate(cfg, logging.NamedLogger("test")), &buf)
+
+			// Act
+			err := o.Write(resp)
+
+			// Assert
+			if expect != nil {
+				Expect(err).To(MatchError(expect))
+			} else {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(buf.String()).To(MatchRegexp(`^\d+\.\d{3}$`))
+			}
+		},
+		Entry("total", "${response:timing=total}", true, nil),
+		Entry("dns", "${response:timing=dns}", true, nil),
+		Entry("connect", "${response:timing=connect}", true, nil),
+		Entry("tls", "${response:timing=tls}", true, nil),
+		Entry("ttfb", "${response:timing=ttfb}", true, nil),
+		Entry("transfer", "${response:timing=transfer}", true, nil),
+		Entry("unknown", "${response:timing=nope}", true, ErrUnknownTiming),
+		Entry("not traced", "${response:timing=total}", false, ErrNoTimings),
+	)
+
+	Describe("tls tokens", func() {
+		var server *httptest.Server
+		BeforeEach(func() {
+			server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			server.EnableHTTP2 = true
+			server.StartTLS()
+		})
+		AfterEach(func() {
+			server.Close()
+		})
+
+		DescribeTable("resolve",
+			func(template string, expect func() string, expectErr error) {
+				// Arrange
+				cfg := config.NewConfig()
+				cfg.Output.Template = template
+				req, _ := http.NewRequest("GET", server.URL, nil)
+				resp, err := server.Client().Do(trace.WithTrace(req, trace.New()))
+				Expect(err).ToNot(HaveOccurred())
+				var buf bytes.Buffer
+				o := newTextOutputter(cfg.Output, newTemplate(cfg, logging.NamedLogger("test")), &buf)
+
+				// Act
+				err = o.Write(resp)
+
+				// Assert
+				if expectErr != nil {
+					Expect(err).To(MatchError(expectErr))
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(buf.String()).To(Equal(expect()))
+				}
+			},
+			Entry("version", "${response:tls=version}", func() string { return "TLS 1.3" }, nil),
+			Entry("cipher", "${response:tls=cipher}", func() string { return "TLS_AES_128_GCM_SHA256" }, nil),
+			Entry("alpn", "[${response:tls=alpn}]", func() string { return "[h2]" }, nil),
+			Entry("peer subject", "${response:tls=peer-subject}", func() string { return server.Certificate().Subject.String() }, nil),
+			Entry("peer issuer", "${response:tls=peer-issuer}", func() string { return server.Certificate().Issuer.String() }, nil),
+			Entry("peer not after", "${response:tls=peer-not-after}", func() string { return server.Certificate().NotAfter.UTC().Format(time.RFC3339) }, nil),
+			Entry("peer sans", "${response:tls=peer-sans}", func() string { return "example.com,*.example.com,127.0.0.1,::1" }, nil),
+			Entry("remote addr", "${response:tls=remote-addr}", func() string { return server.Listener.Addr().String() }, nil),
+			Entry("remote addr shortcut", "${response:remote-addr}", func() string { return server.Listener.Addr().String() }, nil),
+			Entry("unknown", "${response:tls=nope}", nil, ErrUnknownTLSDetail),
+		)
+	})
+
+	It("reports tls tokens for plain http responses", func() {
+		// Arrange
+		cfg := config.NewConfig()
+		cfg.Output.Template = "${response:tls=version}"
+		var buf bytes.Buffer
+		o := newTextOutputter(cfg.Output, newTemplate(cfg, logging.NamedLogger("test")), &buf)
+
+		// Act
+		err := o.Write(newTestResponse("application/json", `{}`))
+
+		// Assert
+		Expect(err).To(MatchError(ErrNoTLS))
+	})
+
+	It("reports missing paths", func() {
+		// Arrange
+		cfg := config.NewConfig()
+		cfg.Output.Template = "${response:json=data.missing}"
+		var buf bytes.Buffer
+		o := newTextOutputter(cfg.Output, newTemplate(cfg, logging.NamedLogger("test")), &buf)
+
+		// Act
+		err := o.Write(newTestResponse("application/json", `{"data":{}}`))
+
+		// Assert
+		Expect(err).To(MatchError(ErrResolveFailed))
+		Expect(err).To(MatchError(jsonpath.ErrPathNotFound))
+		Expect(err.Error()).To(ContainSubstring("${response:json=data.missing}"))
+		Expect(buf.Len()).To(BeZero())
+	})
+})
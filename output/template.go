@@ -1,49 +1,140 @@
 package output
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"os"
 	"strings"
 
 	"github.com/keithpaterson/go-tools/resolver"
 	"github.com/keithpaterson/postal/config"
+	postalresolver "github.com/keithpaterson/postal/resolver"
 	"go.uber.org/zap"
 )
 
+var (
+	ErrInvalidTemplate  = errors.New("invalid template")
+	ErrTemplateNotFound = errors.New("template not found")
+)
+
 type template struct {
-	cfg *config.Config
-	log *zap.SugaredLogger
+	cfg       *config.Config
+	log       *zap.SugaredLogger
+	formatter *bodyFormatter
 }
 
 func newTemplate(cfg *config.Config, log *zap.SugaredLogger) *template {
 	return &template{cfg: cfg, log: log.Named("template")}
 }
 
-func (t *template) Apply(resp *http.Response) string {
-	template := t.cfg.Output.Template
+// withFormatter sets the formatter used for ${response:body} tokens
+func (t *template) withFormatter(formatter *bodyFormatter) *template {
+	t.formatter = formatter
+	return t
+}
+
+// Apply resolves the template; tokens that cannot be resolved (e.g. a missing json path) are reported in the error.
+func (t *template) Apply(resp *http.Response) (string, error) {
+	template, err := t.source()
+	if err != nil {
+		return "", err
+	}
+
+	if t.cfg.Output.TemplateEngine() == config.EngineGo {
+		if template == "" {
+			template = "{{ .Response.Body }}"
+		}
+		return t.applyGo(template, resp)
+	}
+
 	if template == "" {
 		template = "${response:body}"
 	}
+	return t.resolve(template, resp)
+}
+
+// source finds the template text: inline, named ("@name") or loaded from a file ("file:name").
+// Escapes are only processed for inline templates; files can just contain the actual characters.
+func (t *template) source() (string, error) {
+	template := t.cfg.Output.Template
+	if name, ok := strings.CutPrefix(template, "@"); ok {
+		if template, ok = t.cfg.Output.Templates[name]; !ok {
+			return "", fmt.Errorf("%w: no template named '%s'", ErrTemplateNotFound, name)
+		}
+	}
+
+	if filename, ok := strings.CutPrefix(template, "file:"); ok {
+		data, err := os.ReadFile(strings.TrimSpace(filename))
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrTemplateNotFound, err)
+		}
+		return string(data), nil
+	}
 
-	return t.resolve(t.fixTemplate(template), resp)
+	return t.fixTemplate(template), nil
 }
 
+// fixTemplate replaces common escapes for non-token text we might find in the template specification
 func (t *template) fixTemplate(input string) string {
-	replacer := strings.NewReplacer(templateReplacements...)
-	return replacer.Replace(input)
-}
+	if t.cfg.Output.NoEscapes {
+		return input
+	}
 
-func (t *template) resolve(input string, resp *http.Response) string {
-	return resolver.NewResolver(&resolver.ResolverConfig{Properties: resolver.Properties(t.cfg.Properties)}).
-		WithStandardResolvers().
-		WithResolver("response", newResponseResolver(resp, t.log)).
-		Resolve(input)
+	tab := "\t"
+	if t.cfg.Output.TabWidth > 0 {
+		tab = strings.Repeat(" ", t.cfg.Output.TabWidth)
+	}
+	replacer := strings.NewReplacer(
+		"\\n", "\n", // newlines become new lines
+		"\\t", tab, // tabs expand to TabWidth spaces
+	)
+	if t.cfg.Output.TemplateEngine() != config.EngineGo {
+		return replacer.Replace(input)
+	}
+
+	// go actions are left alone; their string literals (e.g. printf "%d\n") are unquoted by the go parser
+	var result strings.Builder
+	for {
+		start := strings.Index(input, "{{")
+		end := -1
+		if start >= 0 {
+			end = strings.Index(input[start:], "}}")
+		}
+		if end < 0 {
+			// no more actions (an unterminated action is reported by the parser)
+			if start < 0 {
+				start = len(input)
+			}
+			result.WriteString(replacer.Replace(input[:start]))
+			result.WriteString(input[start:])
+			return result.String()
+		}
+		end += start + len("}}")
+		result.WriteString(replacer.Replace(input[:start]))
+		result.WriteString(input[start:end])
+		input = input[end:]
+	}
 }
 
-var (
-	// common replacements for non-token text we might find in the template specification
-	// TODO(keithpaterson): make tab expansion configurable (via config.OutputConfig.Options?)
-	templateReplacements = []string{
-		"\\n", "\n", // newlines become new lines
-		"\\t", "    ", // tabs expand to 4 spaces
+func (t *template) resolve(input string, resp *http.Response) (string, error) {
+	response := newResponseResolver(resp, t.log).WithNamespaces(t.cfg.Output.Namespaces).WithFormatter(t.formatter)
+	root := resolver.NewResolver(&resolver.ResolverConfig{Properties: resolver.Properties(t.cfg.Properties)}).
+		WithStandardResolvers().
+		WithResolver("response", response)
+	if resp.Request != nil {
+		root.WithResolver("request", t.newRequestResolver(resp))
 	}
-)
+
+	// filters first; otherwise the resolvers would try to resolve the whole pipeline
+	// this is the last pass, so every token is resolved (or defaulted) now
+	input, filterErr := postalresolver.ResolveFilters(input, func(base string, optional bool) (string, bool) {
+		if optional {
+			// e.g. a missing json path with "| default(none)" is not an error
+			return response.ignoringErrors(func() string { return root.Resolve(base) }), true
+		}
+		return root.Resolve(base), true
+	})
+	result := root.Resolve(input)
+	return result, errors.Join(response.Err(), filterErr)
+}
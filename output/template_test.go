@@ -0,0 +1,123 @@
+package output
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/keithpaterson/postal/config"
+	"github.com/keithpaterson/postal/logging"
+	postalresolver "github.com/keithpaterson/postal/resolver"
+	"github.com/keithpaterson/postal/validate"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Template", func() {
+	DescribeTable("Apply",
+		func(setup func(out *config.OutputConfig), expect string) {
+			// Arrange
+			cfg := config.NewConfig()
+			cfg.Output.Templates = map[string]string{
+				"short": "${response:status-code}",
+				"file":  "file:testdata/summary.tmpl",
+			}
+			setup(&cfg.Output)
+			t := newTemplate(cfg, logging.NamedLogger("test"))
+
+			// Act
+			actual, err := t.Apply(newTestResponse("application/json", "hi"))
+
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actual).To(Equal(expect))
+		},
+		Entry("default escapes", func(out *config.OutputConfig) { out.Template = `a\n\tb` }, "a\n    b"),
+		Entry("tab width", func(out *config.OutputConfig) { out.Template = `\tb`; out.TabWidth = 2 }, "  b"),
+		Entry("real tabs", func(out *config.OutputConfig) { out.Template = `\tb`; out.TabWidth = 0 }, "\tb"),
+		Entry("no escapes", func(out *config.OutputConfig) { out.Template = `a\n\tb`; out.NoEscapes = true }, `a\n\tb`),
+		Entry("file", func(out *config.OutputConfig) { out.Template = "file:testdata/summary.tmpl" }, "status=200 OK\n\tbody=hi\n"),
+		Entry("named", func(out *config.OutputConfig) { out.Template = "@short" }, "200"),
+		Entry("named file", func(out *config.OutputConfig) { out.Template = "@file" }, "status=200 OK\n\tbody=hi\n"),
+		Entry("named go template", func(out *config.OutputConfig) {
+			out.Engine = "go"
+			out.Templates["go"] = `{{ .Status }}\n`
+			out.Template = "@go"
+		}, "200\n"),
+		Entry("filters", func(out *config.OutputConfig) {
+			out.Template = "${response:header=Content-Type | urlencode} ${response:body | sha256} ${response:json=missing | default(-)}"
+		}, "application%2Fjson 8f434346648f6b96df89dda901c5176b10a6d83961dd3c1ac88b59b2dc327aa4 -"),
+	)
+
+	It("resolves request tokens", func() {
+		// Arrange
+		cfg := config.NewConfig()
+		cfg.Output.Template = "${request:method} ${request:url} ${request:path} ${request:header=Authorization} ${request:header=X-Test} ${request:body} ${request:content-length} ${request:attempt}"
+		req, _ := http.NewRequest("POST", "https://test.io/api/items?x=1", strings.NewReader(`{"a":1}`))
+		req.Header.Set("Authorization", "Bearer secret")
+		req.Header.Set("X-Test", "yes")
+		resp := newTestResponse("application/json", "hi")
+		resp.Request = req
+		t := newTemplate(cfg, logging.NamedLogger("test"))
+
+		// Act
+		actual, err := t.Apply(resp)
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actual).To(Equal(`POST https://test.io/api/items?x=1 /api/items Bearer ******** yes {"a":1} 7 1`))
+	})
+
+	It("keeps defaults for response and request tokens when the config is validated", func() {
+		// Arrange
+		cfg := config.NewConfig()
+		cfg.Request.Method = "GET"
+		cfg.Request.URL = "https://test.io/api"
+		cfg.Properties["name"] = " Mousse "
+		cfg.Output.Template = "loc=${response:header=Location | default(none)} method=${request:method | default(x) | lower} name=${name | trim}"
+		validated, err := validate.ValidateConfig(cfg)
+		Expect(err).ToNot(HaveOccurred())
+		req, _ := http.NewRequest("GET", "https://test.io/api", nil)
+		resp := newTestResponse("application/json", "hi")
+		resp.Request = req
+		t := newTemplate(validated, logging.NamedLogger("test"))
+
+		// Act
+		actual, err := t.Apply(resp)
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(validated.Output.Template).To(Equal("loc=${response:header=Location | default(none)} method=${request:method | default(x) | lower} name=Mousse"))
+		Expect(actual).To(Equal("loc=none method=get name=Mousse"))
+	})
+
+	DescribeTable("errors",
+		func(template string) {
+			// Arrange
+			cfg := config.NewConfig()
+			cfg.Output.Template = template
+			t := newTemplate(cfg, logging.NamedLogger("test"))
+
+			// Act
+			_, err := t.Apply(newTestResponse("application/json", "hi"))
+
+			// Assert
+			Expect(err).To(MatchError(ErrTemplateNotFound))
+		},
+		Entry("missing name", "@missing"),
+		Entry("missing file", "file:testdata/missing.tmpl"),
+	)
+
+	It("reports failed filters", func() {
+		// Arrange
+		cfg := config.NewConfig()
+		cfg.Output.Template = "${response:body | b64dec}"
+		t := newTemplate(cfg, logging.NamedLogger("test"))
+
+		// Act
+		_, err := t.Apply(newTestResponse("application/json", "not base64!"))
+
+		// Assert
+		Expect(err).To(MatchError(postalresolver.ErrFilterFailed))
+	})
+})
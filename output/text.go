@@ -17,6 +17,10 @@ func newTextOutputter(_ config.OutputConfig, template *template, writer io.Write
 }
 
 func (o *textOutputter) Write(resp *http.Response) error {
-	_, err := o.writer.Write([]byte(o.template.Apply(resp)))
+	text, err := o.template.Apply(resp)
+	if err != nil {
+		return err
+	}
+	_, err = o.writer.Write([]byte(text))
 	return err
 }
@@ -0,0 +1,209 @@
+package output
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/keithpaterson/postal/config"
+	"github.com/keithpaterson/postal/redact"
+	"github.com/keithpaterson/postal/trace"
+)
+
+const (
+	// request bodies longer than this are truncated
+	previewLength = 1024
+
+	verboseInfo     = "* "
+	verboseRequest  = "> "
+	verboseResponse = "< "
+)
+
+// verboseOutputter writes everything it knows about the exchange, in the style of 'curl -v':
+// TLS details, then the request and response lines and headers for every hop, then the response body.
+type verboseOutputter struct {
+	cfg       *config.Config
+	writer    io.Writer
+	formatter *bodyFormatter
+}
+
+func newVerboseOutputter(cfg *config.Config, writer io.Writer) *verboseOutputter {
+	return &verboseOutputter{cfg: cfg, writer: writer}
+}
+
+// withFormatter sets the formatter used for the response body and colors
+func (o *verboseOutputter) withFormatter(formatter *bodyFormatter) *verboseOutputter {
+	o.formatter = formatter
+	return o
+}
+
+func (o *verboseOutputter) Write(resp *http.Response) error {
+	w := &verboseWriter{writer: o.writer, maskHeader: redact.New(o.cfg).Header}
+	if o.formatter != nil {
+		w.color = o.formatter.color
+	}
+
+	// without a trace (e.g. a custom client) the best we can do is the final request
+	hops := []trace.Hop{{Request: resp.Request, Response: resp}}
+	t := trace.FromResponse(resp)
+	if t != nil && len(t.Hops()) > 0 {
+		hops = t.Hops()
+	}
+
+	host := ""
+	for index, hop := range hops {
+		if hop.Request == nil {
+			continue
+		}
+		if index > 0 {
+			w.line(verboseInfo, "Following redirect to %s", hop.Request.URL)
+		}
+		if hop.Response != nil && hop.Response.TLS != nil && hop.Request.URL.Host != host {
+			w.tls(hop.Response.TLS)
+		}
+		host = hop.Request.URL.Host
+
+		w.request(hop.Request, hop.Response)
+		if hop.Err != nil {
+			w.line(verboseInfo, "Error: %s", hop.Err)
+		}
+		if hop.Response != nil {
+			w.response(hop.Response)
+		}
+	}
+
+	if w.err != nil {
+		return w.err
+	}
+	err := o.writeBody(resp)
+	if t != nil {
+		t.MarkDone()
+	}
+	return err
+}
+
+func (o *verboseOutputter) writeBody(resp *http.Response) error {
+	// stream the body unless it needs formatting
+	if !o.formatter.active() {
+		_, err := io.Copy(o.writer, resp.Body)
+		return err
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	_, err = o.writer.Write(o.formatter.Format(resp.Header.Get("content-type"), body))
+	return err
+}
+
+type verboseWriter struct {
+	writer     io.Writer
+	maskHeader func(name string, value string) string
+	color      *colorizer
+	err        error
+}
+
+func (w *verboseWriter) line(prefix string, format string, args ...any) {
+	if w.err != nil {
+		return
+	}
+	// blank lines are just the prefix, without trailing whitespace
+	line := strings.TrimRight(fmt.Sprintf(prefix+format, args...), " ")
+	_, w.err = fmt.Fprintln(w.writer, line)
+}
+
+func (w *verboseWriter) tls(state *tls.ConnectionState) {
+	w.line(verboseInfo, "TLS connection using %s / %s", tls.VersionName(state.Version), tls.CipherSuiteName(state.CipherSuite))
+	if state.NegotiatedProtocol != "" {
+		w.line(verboseInfo, "ALPN: server accepted %s", state.NegotiatedProtocol)
+	}
+	if state.ServerName != "" {
+		w.line(verboseInfo, "Server name: %s", state.ServerName)
+	}
+	if state.DidResume {
+		w.line(verboseInfo, "Resumed TLS session")
+	}
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		w.line(verboseInfo, "Server certificate:")
+		w.line(verboseInfo, "  subject: %s", cert.Subject)
+		w.line(verboseInfo, "  issuer: %s", cert.Issuer)
+		w.line(verboseInfo, "  valid from: %s", cert.NotBefore.Format(time.RFC1123))
+		w.line(verboseInfo, "  valid until: %s", cert.NotAfter.Format(time.RFC1123))
+		if len(cert.DNSNames) > 0 {
+			w.line(verboseInfo, "  names: %s", strings.Join(cert.DNSNames, ", "))
+		}
+	}
+}
+
+func (w *verboseWriter) request(req *http.Request, resp *http.Response) {
+	// the request's Proto is not what was actually negotiated; the response knows
+	proto := req.Proto
+	if resp != nil {
+		proto = resp.Proto
+	}
+	if proto == "" {
+		proto = "HTTP/1.1"
+	}
+
+	w.line(verboseRequest, "%s %s %s", req.Method, req.URL.RequestURI(), proto)
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	w.line(verboseRequest, "Host: %s", host)
+	w.headers(verboseRequest, req.Header, w.maskHeader)
+	w.line(verboseRequest, "")
+
+	if preview, ok := w.bodyPreview(req); ok {
+		w.line(verboseRequest, "%s", preview)
+	}
+}
+
+func (w *verboseWriter) response(resp *http.Response) {
+	w.line(verboseResponse, "%s", w.color.Status(resp.StatusCode, resp.Proto+" "+resp.Status))
+	w.headers(verboseResponse, resp.Header, func(_ string, value string) string { return value })
+	w.line(verboseResponse, "")
+}
+
+func (w *verboseWriter) headers(prefix string, headers http.Header, mask func(string, string) string) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		for _, value := range headers[name] {
+			w.line(prefix, "%s: %s", w.color.HeaderName(name), mask(name, value))
+		}
+	}
+}
+
+// bodyPreview reads a copy of the request body, which http.NewRequest makes available via GetBody
+func (w *verboseWriter) bodyPreview(req *http.Request) (string, bool) {
+	if req.GetBody == nil || req.ContentLength == 0 {
+		return "", false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return "", false
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, previewLength+1))
+	if err != nil || len(data) == 0 {
+		return "", false
+	}
+	if !utf8.Valid(data[:min(len(data), previewLength)]) {
+		return fmt.Sprintf("[%d bytes of binary data]", req.ContentLength), true
+	}
+	if len(data) > previewLength {
+		return fmt.Sprintf("%s... [%d bytes total]", data[:previewLength], req.ContentLength), true
+	}
+	return string(data), true
+}
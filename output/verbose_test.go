@@ -0,0 +1,94 @@
+package output
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/keithpaterson/postal/config"
+	"github.com/keithpaterson/postal/trace"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Verbose Output", func() {
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/start", func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "/end?x=1", http.StatusTemporaryRedirect)
+		})
+		mux.HandleFunc("/end", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Reply", "done")
+			w.Write([]byte("the body"))
+		})
+		server = httptest.NewTLSServer(mux)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("writes every hop", func() {
+		// Arrange
+		client := server.Client()
+		client.Transport = trace.NewTransport(client.Transport)
+		req, _ := http.NewRequest("POST", server.URL+"/start", strings.NewReader(`{"a":1}`))
+		req.Header.Set("Authorization", "Bearer secret")
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(trace.WithTrace(req, trace.New()))
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+
+		var buf bytes.Buffer
+		o := newVerboseOutputter(config.NewConfig(), &buf)
+
+		// Act
+		err = o.Write(resp)
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		lines := strings.Split(buf.String(), "\n")
+		Expect(lines).To(ContainElement(HavePrefix("* TLS connection using TLS")))
+		Expect(lines).To(ContainElement(HavePrefix("*   subject: O=Acme Co")))
+		Expect(lines).To(ContainElements(
+			"> POST /start HTTP/1.1",
+			"> Authorization: Bearer ********",
+			"> Content-Type: application/json",
+			`> {"a":1}`,
+			"< HTTP/1.1 307 Temporary Redirect",
+			"< Location: /end?x=1",
+			"* Following redirect to "+server.URL+"/end?x=1",
+			"> POST /end?x=1 HTTP/1.1",
+			"< HTTP/1.1 200 OK",
+			"< X-Reply: done",
+		))
+		Expect(buf.String()).To(HaveSuffix("<\nthe body"))
+		// only one connection, so the TLS details are only written once
+		Expect(strings.Count(buf.String(), "* TLS connection")).To(Equal(1))
+	})
+
+	It("writes the final request without a trace", func() {
+		// Arrange
+		req, _ := http.NewRequest("GET", server.URL+"/end", nil)
+		resp, err := server.Client().Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+
+		var buf bytes.Buffer
+		o := newVerboseOutputter(config.NewConfig(), &buf)
+
+		// Act
+		err = o.Write(resp)
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buf.String()).To(HavePrefix("* TLS connection"))
+		Expect(buf.String()).To(ContainSubstring("> GET /end HTTP/1.1\n"))
+		Expect(buf.String()).To(HaveSuffix("< HTTP/1.1 200 OK\n< Content-Length: 8\n< Content-Type: text/plain; charset=utf-8\n< Date: " + resp.Header.Get("Date") + "\n< X-Reply: done\n<\nthe body"))
+	})
+})
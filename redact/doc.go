@@ -0,0 +1,11 @@
+// package redact masks sensitive values before configuration, headers or properties are printed or logged.
+//
+// The values that are masked are the union of the built-in defaults (see DefaultHeaders and DefaultFields)
+// and the values listed in the config.RedactConfig structure.  For example:
+//
+//	r := redact.New(cfg)
+//	log.Debugf("%#v", *r.Config(cfg))
+//	fmt.Println("authorization:", r.Header("authorization", value))
+//
+// Masking is disabled entirely when config.RuntimeConfig.ShowSecrets is set.
+package redact
@@ -0,0 +1,163 @@
+package redact
+
+import (
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/keithpaterson/postal/config"
+)
+
+// Mask replaces a sensitive value
+const Mask = "********"
+
+var (
+	// DefaultHeaders are always masked
+	DefaultHeaders = []string{"authorization", "proxy-authorization", "cookie", "set-cookie", "x-api-key", "x-amz-security-token"}
+
+	// DefaultFields are always masked
+	DefaultFields = []string{
+		"jwt.signing-key",
+		"oauth2.client-secret", "oauth2.password", "oauth2.refresh-token",
+		"auth.secret-key", "auth.session-token",
+		"signing.secret",
+	}
+)
+
+type redactor struct {
+	headers    []string
+	properties []string
+	fields     []string
+	show       bool
+}
+
+// New returns a redactor configured using the config's [redact] section and runtime flags.
+func New(cfg *config.Config) *redactor {
+	r := &redactor{
+		headers:    slices.Clone(DefaultHeaders),
+		properties: slices.Clone(cfg.Redact.Properties),
+		fields:     append(slices.Clone(DefaultFields), cfg.Redact.Fields...),
+		show:       cfg.Runtime.ShowSecrets,
+	}
+	for _, name := range cfg.Redact.Headers {
+		r.headers = append(r.headers, strings.ToLower(name))
+	}
+	return r
+}
+
+// Value masks a sensitive value.
+func (r *redactor) Value(value string) string {
+	if r.show || value == "" {
+		return value
+	}
+	return Mask
+}
+
+// Header returns the header value, masked if the header is sensitive.
+//
+// Values that look like "<scheme> <credentials>" (e.g. "Bearer xxx") keep the scheme so that
+// the output is still useful when troubleshooting.
+func (r *redactor) Header(name string, value string) string {
+	if !r.IsSensitiveHeader(name) || value == "" {
+		return value
+	}
+	if scheme, _, ok := strings.Cut(value, " "); ok && scheme != "" && !strings.ContainsAny(scheme, "=,;") {
+		return scheme + " " + Mask
+	}
+	return Mask
+}
+
+func (r *redactor) IsSensitiveHeader(name string) bool {
+	return !r.show && slices.Contains(r.headers, strings.ToLower(name))
+}
+
+// Headers returns a copy of the headers with sensitive values masked.
+func (r *redactor) Headers(headers http.Header) http.Header {
+	if r.show {
+		return headers
+	}
+	result := make(http.Header, len(headers))
+	for name, values := range headers {
+		masked := slices.Clone(values)
+		for index, value := range masked {
+			masked[index] = r.Header(name, value)
+		}
+		result[name] = masked
+	}
+	return result
+}
+
+// Property returns the property value, masked if the property is sensitive.
+func (r *redactor) Property(name string, value any) any {
+	if r.show || !slices.Contains(r.properties, name) {
+		return value
+	}
+	return Mask
+}
+
+// Config returns a copy of the config with sensitive values masked; the original is not modified.
+func (r *redactor) Config(cfg *config.Config) *config.Config {
+	if r.show {
+		return cfg
+	}
+
+	masked := *cfg
+	masked.Request.Headers = make(config.HeadersConfig, len(cfg.Request.Headers))
+	for name, value := range cfg.Request.Headers {
+		masked.Request.Headers[name] = r.Header(name, value)
+	}
+	masked.Properties = maps.Clone(cfg.Properties)
+	for name, value := range masked.Properties {
+		masked.Properties[name] = r.Property(name, value)
+	}
+
+	root := reflect.ValueOf(&masked).Elem()
+	for _, field := range r.fields {
+		r.maskField(root, strings.Split(field, "."))
+	}
+	return &masked
+}
+
+// maskField follows the (toml) field names in 'path' and masks the field it finds.
+// Unknown fields are ignored.
+func (r *redactor) maskField(value reflect.Value, path []string) {
+	if value.Kind() != reflect.Struct || len(path) == 0 {
+		return
+	}
+
+	field, ok := r.findField(value, path[0])
+	if !ok {
+		return
+	}
+	if len(path) > 1 {
+		r.maskField(field, path[1:])
+		return
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(r.Value(field.String()))
+	case reflect.Slice:
+		// replace (rather than modify) the slice; it is shared with the original config
+		if field.Type().Elem().Kind() == reflect.String {
+			masked := reflect.MakeSlice(field.Type(), field.Len(), field.Len())
+			for index := 0; index < field.Len(); index++ {
+				masked.Index(index).SetString(r.Value(field.Index(index).String()))
+			}
+			field.Set(masked)
+		}
+	}
+}
+
+func (r *redactor) findField(value reflect.Value, name string) (reflect.Value, bool) {
+	valueType := value.Type()
+	for index := 0; index < valueType.NumField(); index++ {
+		tag, _, _ := strings.Cut(valueType.Field(index).Tag.Get("toml"), ",")
+		if tag == name {
+			return value.Field(index), true
+		}
+	}
+	return reflect.Value{}, false
+}
@@ -0,0 +1,84 @@
+package redact
+
+import (
+	"net/http"
+
+	"github.com/keithpaterson/postal/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func testConfig() *config.Config {
+	cfg := config.NewConfig()
+	cfg.Request.Headers = config.HeadersConfig{"Authorization": "Bearer abc.def", "X-Custom": "custom", "accept": "*/*"}
+	cfg.Properties = config.Properties{"password": "hunter2", "name": "me"}
+	cfg.JWT.SigningKey = "string:my signing key"
+	cfg.OAuth2.ClientID = "client"
+	cfg.OAuth2.ClientSecret = "secret"
+	cfg.OAuth2.Scopes = []string{"read", "write"}
+	cfg.Redact = config.RedactConfig{Headers: []string{"x-CUSTOM"}, Properties: []string{"password"}, Fields: []string{"oauth2.scopes", "oauth2.not-a-field", "not.a.field"}}
+	return cfg
+}
+
+var _ = Describe("Redact", func() {
+	DescribeTable("Header",
+		func(name string, value string, expect string) {
+			// Act
+			actual := New(testConfig()).Header(name, value)
+
+			// Assert
+			Expect(actual).To(Equal(expect))
+		},
+		Entry("keeps the scheme", "Authorization", "Bearer abc.def", "Bearer ********"),
+		Entry("without scheme", "authorization", "abc.def", Mask),
+		Entry("default header", "Cookie", "session=1; other=2", Mask),
+		Entry("configured header", "X-Custom", "custom", Mask),
+		Entry("other header", "Accept", "*/*", "*/*"),
+		Entry("empty value", "Authorization", "", ""),
+	)
+
+	It("masks http headers", func() {
+		// Arrange
+		headers := http.Header{"Authorization": {"Basic abc"}, "Accept": {"*/*"}}
+
+		// Act
+		actual := New(testConfig()).Headers(headers)
+
+		// Assert
+		Expect(actual).To(Equal(http.Header{"Authorization": {"Basic " + Mask}, "Accept": {"*/*"}}))
+		Expect(headers.Get("Authorization")).To(Equal("Basic abc"))
+	})
+
+	It("masks the config without modifying the original", func() {
+		// Arrange
+		cfg := testConfig()
+
+		// Act
+		actual := New(cfg).Config(cfg)
+
+		// Assert
+		Expect(actual.Request.Headers).To(Equal(config.HeadersConfig{"Authorization": "Bearer " + Mask, "X-Custom": Mask, "accept": "*/*"}))
+		Expect(actual.Properties).To(Equal(config.Properties{"password": Mask, "name": "me"}))
+		Expect(actual.JWT.SigningKey).To(Equal(Mask))
+		Expect(actual.OAuth2.ClientID).To(Equal("client"))
+		Expect(actual.OAuth2.ClientSecret).To(Equal(Mask))
+		Expect(actual.OAuth2.Scopes).To(Equal([]string{Mask, Mask}))
+		Expect(actual.OAuth2.Password).To(BeEmpty())
+
+		Expect(cfg).To(Equal(testConfig()))
+	})
+
+	It("shows secrets when asked to", func() {
+		// Arrange
+		cfg := testConfig()
+		cfg.Runtime.ShowSecrets = true
+		r := New(cfg)
+
+		// Act & Assert
+		Expect(r.Config(cfg)).To(BeIdenticalTo(cfg))
+		Expect(r.Header("Authorization", "Bearer abc")).To(Equal("Bearer abc"))
+		Expect(r.Property("password", "hunter2")).To(Equal("hunter2"))
+		Expect(r.Value("secret")).To(Equal("secret"))
+	})
+})
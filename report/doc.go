@@ -0,0 +1,23 @@
+/*
+package report writes the outcome of a request (and its [[assert]] entries) in formats that CI systems understand.
+
+The report is selected using [output] report (or 'postal send --report'), which is "kind" or "kind:filename":
+  - "junit": JUnit XML, as consumed by Jenkins, GitLab and most other CI systems, e.g. "junit:results.xml"
+  - "tap": the Test Anything Protocol (version 13), e.g. "tap" or "tap:results.tap"
+
+The filename defaults to "postal-report.xml" (junit) or "postal-report.tap" (tap), so that the report doesn't
+mix with the response, which is written to stdout unless [output] filename says otherwise.
+"stdout" and "stderr" can also be used, e.g. "junit:stdout" together with '--out-file response.json'.
+
+Each run replaces the report, unless [output] append is set: then the request is added
+to the existing report (as another JUnit test suite, or as more TAP test points), so a CI job that sends several
+requests gets one report with all of them (delete the file at the start of the job).
+A file that isn't a report of the same kind is left alone and the error is reported.
+
+Each request is a test suite (named after its method and URL) with one test case per assertion.
+Failures describe the expected and actual values, the request, its response status and how long it took.
+A request without assertions is a single test case that passes when the request succeeds;
+if the request fails before its assertions can run (e.g. the server cannot be reached) the failure is
+reported as an error test case instead.
+*/
+package report
@@ -0,0 +1,126 @@
+package report
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Errors    int         `xml:"errors,attr"`
+	Time      string      `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// readJUnit reads the suites from an existing report; it returns nil if the file doesn't exist
+func readJUnit(filename string) (*junitSuites, error) {
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var suites junitSuites
+	if err = xml.Unmarshal(data, &suites); err != nil {
+		return nil, fmt.Errorf("'%s' is not a JUnit report: %w", filename, err)
+	}
+	return &suites, nil
+}
+
+// writeJUnit adds the run to suites (which can be nil) and writes them
+func writeJUnit(w io.Writer, suites *junitSuites, run Run) error {
+	if suites == nil {
+		suites = &junitSuites{Name: "postal"}
+	}
+	suites.Suites = append(suites.Suites, newJUnitSuite(run))
+
+	var seconds float64
+	suites.Tests, suites.Failures, suites.Errors = 0, 0, 0
+	for _, suite := range suites.Suites {
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+		value, _ := strconv.ParseFloat(suite.Time, 64)
+		seconds += value
+	}
+	suites.Time = fmt.Sprintf("%.3f", seconds)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func newJUnitSuite(run Run) junitSuite {
+	seconds := formatSeconds(run.Duration)
+	suite := junitSuite{
+		Name:      run.Request,
+		Time:      seconds,
+		Timestamp: time.Now().Add(-run.Duration).UTC().Format("2006-01-02T15:04:05"),
+	}
+	for _, c := range run.testCases() {
+		// assertions are evaluated instantly; the time is spent on the request
+		jc := junitCase{Name: c.name, ClassName: run.Request, Time: formatSeconds(0)}
+		if c.request {
+			jc.Time = seconds
+		}
+		if !c.passed {
+			failure := &junitFailure{Message: c.message, Type: "assertion", Text: strings.Join(run.details(c), "\n")}
+			if c.isError {
+				failure.Type = "error"
+				jc.Error = failure
+				suite.Errors++
+			} else {
+				jc.Failure = failure
+				suite.Failures++
+			}
+		}
+		suite.Cases = append(suite.Cases, jc)
+	}
+	suite.Tests = len(suite.Cases)
+	return suite
+}
+
+func formatSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,140 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/keithpaterson/postal/assertion"
+)
+
+const (
+	KindJUnit = "junit"
+	KindTAP   = "tap"
+)
+
+// the default files, so that reports don't mix with the response (which is written to stdout by default)
+var defaultFilenames = map[string]string{
+	KindJUnit: "postal-report.xml",
+	KindTAP:   "postal-report.tap",
+}
+
+var (
+	ErrInvalidReport = errors.New("invalid report")
+)
+
+// Run describes a request and its outcome
+type Run struct {
+	// Request identifies the request, e.g. "GET https://test.io/orders"
+	Request string
+	// Status is the response status, e.g. "200 OK"; empty if there was no response
+	Status   string
+	Duration time.Duration
+	Results  []assertion.Result
+	// Err is the reason that the request failed, other than its assertions
+	Err error
+}
+
+// testCase is a single result in the report, which can be an assertion or the request itself
+type testCase struct {
+	name     string
+	passed   bool
+	request  bool // the test case is the request itself, rather than an assertion
+	isError  bool // the request failed, rather than an assertion
+	expected string
+	message  string
+}
+
+// Parse splits a report specification ("kind" or "kind:filename") into its kind and filename
+func Parse(spec string) (kind string, filename string, err error) {
+	kind, filename, _ = strings.Cut(spec, ":")
+	if kind != KindJUnit && kind != KindTAP {
+		return "", "", fmt.Errorf("%w: '%s': expected junit or tap", ErrInvalidReport, spec)
+	}
+	if filename == "" {
+		filename = defaultFilenames[kind]
+	}
+	return kind, filename, nil
+}
+
+// Write writes the report described by spec; when appendRun is set, the run is added to an existing report
+// (so that a CI job can report several requests in the same file), otherwise the file is replaced.
+func Write(spec string, run Run, appendRun bool) error {
+	kind, filename, err := Parse(spec)
+	if err != nil {
+		return err
+	}
+
+	var suites *junitSuites
+	var tap *tapReport
+	var w io.Writer
+	switch filename {
+	case "stdout":
+		w = os.Stdout
+	case "stderr":
+		w = os.Stderr
+	default:
+		if appendRun {
+			if kind == KindJUnit {
+				suites, err = readJUnit(filename)
+			} else {
+				tap, err = readTAP(filename)
+			}
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrInvalidReport, err)
+			}
+		}
+		if err = os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidReport, err)
+		}
+		var file *os.File
+		if file, err = os.Create(filename); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidReport, err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	switch kind {
+	case KindJUnit:
+		return writeJUnit(w, suites, run)
+	default:
+		return writeTAP(w, tap, run)
+	}
+}
+
+func (r Run) testCases() []testCase {
+	var cases []testCase
+	for _, result := range r.Results {
+		cases = append(cases, testCase{name: result.Name, passed: result.Passed, expected: result.Expected, message: result.Message})
+	}
+	if r.Err != nil {
+		cases = append(cases, testCase{name: "request", request: true, isError: true, message: r.Err.Error()})
+	} else if len(cases) == 0 {
+		cases = append(cases, testCase{name: "request", request: true, passed: true})
+	}
+	return cases
+}
+
+// details describes a failure, for the body of the report entry
+func (r Run) details(c testCase) []string {
+	var lines []string
+	if c.expected != "" {
+		lines = append(lines, "expected: "+c.expected)
+	}
+	if c.isError {
+		lines = append(lines, "error: "+c.message)
+	} else {
+		lines = append(lines, "actual: "+c.message)
+	}
+	lines = append(lines, "request: "+r.Request)
+	if r.Status != "" {
+		lines = append(lines, "status: "+r.Status)
+	}
+	lines = append(lines, "duration: "+r.Duration.Round(time.Millisecond).String())
+	return lines
+}
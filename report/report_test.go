@@ -0,0 +1,213 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/keithpaterson/postal/assertion"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func newTestRun() Run {
+	return Run{
+		Request:  "GET http://test.io/orders/42",
+		Status:   "200 OK",
+		Duration: 1500 * time.Millisecond,
+		Results: []assertion.Result{
+			{Name: "status 2xx", Passed: true, Expected: "status 2xx"},
+			{Name: "order id", Expected: `json id equals "ord-7"`, Message: `got "ord-42"`},
+		},
+	}
+}
+
+// the timestamp changes with every run
+var timestamp = regexp.MustCompile(`timestamp="[^"]*"`)
+
+var _ = Describe("Report", func() {
+	DescribeTable("Parse",
+		func(spec string, expectKind string, expectFilename string, expectErr error) {
+			// Act
+			kind, filename, err := Parse(spec)
+
+			// Assert
+			if expectErr != nil {
+				Expect(err).To(MatchError(expectErr))
+			} else {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(kind).To(Equal(expectKind))
+				Expect(filename).To(Equal(expectFilename))
+			}
+		},
+		Entry("junit", "junit", "junit", "postal-report.xml", nil),
+		Entry("junit file", "junit:out/results.xml", "junit", "out/results.xml", nil),
+		Entry("tap", "tap", "tap", "postal-report.tap", nil),
+		Entry("tap stderr", "tap:stderr", "tap", "stderr", nil),
+		Entry("unknown", "xunit:results.xml", "", "", ErrInvalidReport),
+	)
+
+	It("writes junit", func() {
+		// Arrange
+		var buf bytes.Buffer
+
+		// Act
+		err := writeJUnit(&buf, nil, newTestRun())
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(timestamp.ReplaceAllString(buf.String(), `timestamp=""`)).To(Equal(`<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="postal" tests="2" failures="1" errors="0" time="1.500">
+  <testsuite name="GET http://test.io/orders/42" tests="2" failures="1" errors="0" time="1.500" timestamp="">
+    <testcase name="status 2xx" classname="GET http://test.io/orders/42" time="0.000"></testcase>
+    <testcase name="order id" classname="GET http://test.io/orders/42" time="0.000">
+      <failure message="got &#34;ord-42&#34;" type="assertion">expected: json id equals &#34;ord-7&#34;&#xA;actual: got &#34;ord-42&#34;&#xA;request: GET http://test.io/orders/42&#xA;status: 200 OK&#xA;duration: 1.5s</failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`))
+	})
+
+	It("writes junit errors", func() {
+		// Arrange
+		run := Run{Request: "GET http://test.io", Duration: time.Second, Err: errors.New("connection refused")}
+		var buf bytes.Buffer
+
+		// Act
+		err := writeJUnit(&buf, nil, run)
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buf.String()).To(ContainSubstring(`tests="1" failures="0" errors="1"`))
+		Expect(buf.String()).To(ContainSubstring(`<testcase name="request" classname="GET http://test.io" time="1.000">`))
+		Expect(buf.String()).To(ContainSubstring(`<error message="connection refused" type="error">error: connection refused&#xA;request: GET http://test.io&#xA;duration: 1s</error>`))
+	})
+
+	It("writes tap", func() {
+		// Arrange
+		var buf bytes.Buffer
+
+		// Act
+		err := writeTAP(&buf, nil, newTestRun())
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buf.String()).To(Equal(`TAP version 13
+1..2
+ok 1 - status 2xx
+not ok 2 - order id
+  ---
+  expected: "json id equals \"ord-7\""
+  actual: "got \"ord-42\""
+  request: "GET http://test.io/orders/42"
+  status: "200 OK"
+  duration: "1.5s"
+  ...
+`))
+	})
+
+	It("writes a passing request without assertions", func() {
+		// Arrange
+		var buf bytes.Buffer
+
+		// Act
+		err := writeTAP(&buf, nil, Run{Request: "GET http://test.io"})
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buf.String()).To(Equal("TAP version 13\n1..1\nok 1 - request\n"))
+	})
+
+	It("writes to a file", func() {
+		// Arrange
+		filename := filepath.Join(GinkgoT().TempDir(), "out", "results.xml")
+
+		// Act
+		err := Write("junit:"+filename, newTestRun(), false)
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		data, err := os.ReadFile(filename)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(HavePrefix("<?xml"))
+	})
+
+	It("adds requests to an existing junit file", func() {
+		// Arrange
+		filename := filepath.Join(GinkgoT().TempDir(), "results.xml")
+		Expect(Write("junit:"+filename, newTestRun(), true)).To(Succeed())
+		second := Run{Request: "GET http://test.io/health", Status: "200 OK", Duration: 500 * time.Millisecond}
+
+		// Act
+		err := Write("junit:"+filename, second, true)
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		suites, err := readJUnit(filename)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(suites.Tests).To(Equal(3))
+		Expect(suites.Failures).To(Equal(1))
+		Expect(suites.Time).To(Equal("2.000"))
+		Expect(suites.Suites).To(HaveLen(2))
+		Expect(suites.Suites[0].Name).To(Equal("GET http://test.io/orders/42"))
+		Expect(suites.Suites[1].Name).To(Equal("GET http://test.io/health"))
+	})
+
+	It("adds requests to an existing tap file", func() {
+		// Arrange
+		filename := filepath.Join(GinkgoT().TempDir(), "results.tap")
+		Expect(Write("tap:"+filename, newTestRun(), true)).To(Succeed())
+
+		// Act
+		err := Write("tap:"+filename, Run{Request: "GET http://test.io/health"}, true)
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		data, err := os.ReadFile(filename)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(HavePrefix("TAP version 13\n1..3\nok 1 - status 2xx\nnot ok 2 - order id\n"))
+		Expect(string(data)).To(HaveSuffix("  ...\nok 3 - request\n"))
+	})
+
+	DescribeTable("replaces existing reports unless appending",
+		func(kind string, expect string) {
+			// Arrange
+			filename := filepath.Join(GinkgoT().TempDir(), "results")
+			Expect(Write(kind+":"+filename, newTestRun(), false)).To(Succeed())
+
+			// Act
+			err := Write(kind+":"+filename, Run{Request: "GET http://test.io/health"}, false)
+
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			data, err := os.ReadFile(filename)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).ToNot(ContainSubstring("order id"))
+			Expect(string(data)).To(ContainSubstring(expect))
+		},
+		Entry("junit", "junit", `tests="1"`),
+		Entry("tap", "tap", "1..1\nok 1 - request\n"),
+	)
+
+	DescribeTable("does not replace files that are not reports",
+		func(kind string) {
+			// Arrange
+			filename := filepath.Join(GinkgoT().TempDir(), "results")
+			Expect(os.WriteFile(filename, []byte("not a report"), 0644)).To(Succeed())
+
+			// Act
+			err := Write(kind+":"+filename, newTestRun(), true)
+
+			// Assert
+			Expect(err).To(MatchError(ErrInvalidReport))
+			data, _ := os.ReadFile(filename)
+			Expect(string(data)).To(Equal("not a report"))
+		},
+		Entry("junit", "junit"),
+		Entry("tap", "tap"),
+	)
+})
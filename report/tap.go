@@ -0,0 +1,79 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var tapPlan = regexp.MustCompile(`^1\.\.\d+$`)
+
+// tapReport holds the test points of an existing report, so that a run can be added to it
+type tapReport struct {
+	// every line except the version and the plan
+	lines []string
+	tests int
+}
+
+// readTAP reads an existing report; returns nil if the file doesn't exist (or is empty)
+func readTAP(filename string) (*tapReport, error) {
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && len(data) == 0) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if !strings.HasPrefix(lines[0], "TAP version") {
+		return nil, fmt.Errorf("'%s' is not a TAP report", filename)
+	}
+	report := &tapReport{}
+	for _, line := range lines[1:] {
+		if tapPlan.MatchString(line) {
+			continue
+		}
+		if strings.HasPrefix(line, "ok ") || strings.HasPrefix(line, "not ok ") {
+			report.tests++
+		}
+		report.lines = append(report.lines, line)
+	}
+	return report, nil
+}
+
+// writeTAP adds the run to the existing report (which can be nil) and writes it
+func writeTAP(w io.Writer, existing *tapReport, run Run) error {
+	if existing == nil {
+		existing = &tapReport{}
+	}
+	cases := run.testCases()
+
+	var b strings.Builder
+	b.WriteString("TAP version 13\n")
+	fmt.Fprintf(&b, "1..%d\n", existing.tests+len(cases))
+	for _, line := range existing.lines {
+		b.WriteString(line + "\n")
+	}
+	for i, c := range cases {
+		number := existing.tests + i + 1
+		if c.passed {
+			fmt.Fprintf(&b, "ok %d - %s\n", number, c.name)
+			continue
+		}
+		fmt.Fprintf(&b, "not ok %d - %s\n", number, c.name)
+		// the diagnostics are a YAML block; quoted strings keep it valid whatever the values contain
+		b.WriteString("  ---\n")
+		for _, line := range run.details(c) {
+			key, value, _ := strings.Cut(line, ": ")
+			fmt.Fprintf(&b, "  %s: %s\n", key, strconv.Quote(value))
+		}
+		b.WriteString("  ...\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
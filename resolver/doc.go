@@ -28,6 +28,15 @@ Resolver supports the following token types:
 	"env"   : "value" is the name of an environment variable.
 		  During resolution, the token is replaced with the value of the environment variable.
 	"jwt"   : "value" must be "token".  The token is replaced wtih the signed JWT token string.
+	"oauth2": "value" must be "access_token" or "token_type".  The token is replaced with the access token
+		  (or its type) fetched from the token endpoint configured in the [oauth2] section.
+		  The token endpoint is trusted using the [cacert] settings, just like the request.
+		  Tokens are not fetched during a dry run.  If the token cannot be fetched, config validation
+		  fails and the request is not sent.
+	"request": only available after the request has been built (e.g. when computing a request signature).
+		  "value" is one of "method", "url", "path", "query", "host", "body", "content-length", "attempt", or
+		  "header=xxx" where "xxx" is the name of a request header.
+		  Output templates can also use these tokens; see the [output] package.
 	"date"|"time"|"datetime" : "value" specifies a date, time, or date+time expression.
 	"epoch" : "value" is the number of seconds since the Unix epoch (January 1, 1970 UTC)
 
@@ -61,6 +70,35 @@ Assuming the current date and time ("now") is Thursday July 6, 2025 3:45:21 pm,
 	"${epoch:now + 1h}" -> "1751820321" (now plus 1 hour)
 	... and so on.
 
+Filters:
+
+A token's value can be passed through one or more filters, separated by "|":
+
+	"${prop:name | trim | upper}"
+	"${env:SECRET | base64}"
+	"${datetime:now | urlencode}"
+
+The supported filters are:
+
+	"trim"            : removes leading and trailing white space
+	"upper", "lower"  : change the case of the value
+	"urlencode"       : escapes the value so that it can be used in a URL query ("a b&c" -> "a+b%26c")
+	"urldecode"       : reverses "urlencode"
+	"base64"          : base64-encodes the value (standard alphabet, padded)
+	"base64url"       : base64-encodes the value (URL-safe alphabet, not padded)
+	"b64dec"          : decodes base64 (either alphabet, with or without padding)
+	"hex"             : hex-encodes the value
+	"sha256"          : the hex-encoded SHA-256 digest of the value
+	"json"            : escapes the value for use inside a JSON string (the quotes are not included)
+	"default(value)"  : replaces an empty value with "value"; as the first filter it also replaces a token
+	                    that cannot be resolved, e.g. "${prop:region | default(us-east-1)}"
+
+A token is only treated as a pipeline when everything after the first "|" is a known filter, so values that
+contain "|" are not affected.  If a filter fails (e.g. "b64dec" on a value that isn't base64), the token is
+left unchanged and the error is logged.
+Tokens that are resolved later (e.g. "${request:...}" before the request is built, or "${response:...}" in
+output templates) keep their filters, including "default", until they can be resolved.
+
 Format Specifiers:
 
 Date/Time formats can be specified in three ways:
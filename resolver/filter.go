@@ -0,0 +1,156 @@
+package resolver
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrFilterFailed = errors.New("filter failed")
+)
+
+var (
+	// matches a "${type:value}" token, captures "type:value"; the same expression that the resolver uses
+	regFilterToken = regexp.MustCompile(`(?U)\${(.*)}`)
+	// matches a "name" or "name(argument)" filter
+	regFilter = regexp.MustCompile(`^([a-z0-9]+)(?:\((.*)\))?$`)
+)
+
+type filterFunc func(value string, argument string) (string, error)
+
+// filters are applied to resolved token values, e.g. "${prop:name | trim | upper}"
+var filters = map[string]filterFunc{
+	"trim":      func(value, _ string) (string, error) { return strings.TrimSpace(value), nil },
+	"upper":     func(value, _ string) (string, error) { return strings.ToUpper(value), nil },
+	"lower":     func(value, _ string) (string, error) { return strings.ToLower(value), nil },
+	"urlencode": func(value, _ string) (string, error) { return url.QueryEscape(value), nil },
+	"urldecode": func(value, _ string) (string, error) { return url.QueryUnescape(value) },
+	"base64":    func(value, _ string) (string, error) { return base64.StdEncoding.EncodeToString([]byte(value)), nil },
+	"base64url": func(value, _ string) (string, error) { return base64.RawURLEncoding.EncodeToString([]byte(value)), nil },
+	"b64dec":    decodeBase64,
+	"hex":       func(value, _ string) (string, error) { return hex.EncodeToString([]byte(value)), nil },
+	"sha256":    hashSHA256,
+	"json":      quoteJson,
+	"default":   defaultValue,
+}
+
+// ResolveFilters resolves the tokens in input that use filters, e.g. "${response:header=Location | trim | urlencode}".
+// The part before the first "|" is resolved using resolve (e.g. "${response:header=Location}") and each filter
+// is then applied to the result in turn.  Tokens without filters are left for the resolver.
+//
+// A token is only treated as a pipeline if everything after the first "|" is a known filter; this way
+// values that contain "|" (e.g. the xpath "//a | //b") are not affected.  Tokens whose value cannot be resolved
+// are left unchanged (unless the first filter is "default"), as are tokens whose filters fail, which are reported in the error.
+//
+// resolve is told whether the token is optional (i.e. the first filter is "default"), so that it can ignore failures.
+// It returns false when the token cannot be resolved yet (e.g. ${response:...} tokens before the response arrives),
+// in which case the whole token, including its filters, is left unchanged for a later pass.
+func ResolveFilters(input string, resolve func(input string, optional bool) (string, bool)) (string, error) {
+	var errs []error
+	result := regFilterToken.ReplaceAllStringFunc(input, func(token string) string {
+		base, pipeline, ok := parsePipeline(regFilterToken.FindStringSubmatch(token)[1])
+		if !ok {
+			return token
+		}
+
+		// "default" also provides a value for tokens that cannot be resolved
+		optional := pipeline[0].name == "default"
+		unresolved := "${" + base + "}"
+		value, ok := resolve(unresolved, optional)
+		if !ok {
+			return token
+		}
+		if value == unresolved {
+			if !optional {
+				return token
+			}
+			value = ""
+		}
+
+		for _, step := range pipeline {
+			var err error
+			if value, err = step.apply(value); err != nil {
+				errs = append(errs, fmt.Errorf("%w: %s: %s: %w", ErrFilterFailed, token, step.name, err))
+				return token
+			}
+		}
+		return value
+	})
+	return result, errors.Join(errs...)
+}
+
+type filterStep struct {
+	name     string
+	argument string
+	filter   filterFunc
+}
+
+func (s filterStep) apply(value string) (string, error) {
+	return s.filter(value, s.argument)
+}
+
+func parsePipeline(token string) (string, []filterStep, bool) {
+	parts := strings.Split(token, "|")
+	if len(parts) < 2 {
+		return "", nil, false
+	}
+
+	steps := make([]filterStep, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		matches := regFilter.FindStringSubmatch(strings.TrimSpace(part))
+		if matches == nil {
+			return "", nil, false
+		}
+		filter, ok := filters[matches[1]]
+		if !ok {
+			return "", nil, false
+		}
+		steps = append(steps, filterStep{name: matches[1], argument: matches[2], filter: filter})
+	}
+
+	base := strings.TrimSpace(parts[0])
+	if base == "" {
+		return "", nil, false
+	}
+	return base, steps, true
+}
+
+// decodeBase64 accepts both the standard and URL-safe alphabets, with or without padding
+func decodeBase64(value string, _ string) (string, error) {
+	value = strings.TrimRight(strings.TrimSpace(value), "=")
+	data, err := base64.RawStdEncoding.DecodeString(value)
+	if err != nil {
+		if data, err = base64.RawURLEncoding.DecodeString(value); err != nil {
+			return "", errors.New("invalid base64 data")
+		}
+	}
+	return string(data), nil
+}
+
+func hashSHA256(value string, _ string) (string, error) {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// quoteJson writes the value as a JSON string, without the surrounding quotes, so that it can be embedded in a JSON string
+func quoteJson(value string, _ string) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data[1 : len(data)-1]), nil
+}
+
+func defaultValue(value string, argument string) (string, error) {
+	if value == "" {
+		return argument, nil
+	}
+	return value, nil
+}
@@ -0,0 +1,78 @@
+package resolver
+
+import (
+	"net/http"
+
+	"github.com/keithpaterson/postal/config"
+
+	"github.com/keithpaterson/go-tools/env"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Filters", func() {
+	var (
+		cfg     *config.Config
+		origEnv env.Setup
+	)
+	BeforeEach(func() {
+		cfg = config.NewConfig()
+		cfg.Properties = config.Properties{"name": " Mousse ", "empty": "", "encoded": "aGVsbG8=", "url": "a b&c", "quote": `say "hi"`}
+		origEnv = env.New().Set("SECRET", "user:pass").Apply()
+	})
+	AfterEach(func() {
+		origEnv.Apply()
+	})
+
+	DescribeTable("Resolve",
+		func(input string, expect string) {
+			// Act
+			actual := NewResolver(cfg).Resolve(input)
+
+			// Assert
+			Expect(actual).To(Equal(expect))
+		},
+		Entry("single filter", "${prop:name | trim}", "Mousse"),
+		Entry("chained filters", "${prop:name | trim | upper}", "MOUSSE"),
+		Entry("default resolver", "${name|trim|lower}", "mousse"),
+		Entry("urlencode", "${prop:url | urlencode}", "a+b%26c"),
+		Entry("urldecode", "${prop:url | urlencode | urldecode}", "a b&c"),
+		Entry("base64", "${env:SECRET | base64}", "dXNlcjpwYXNz"),
+		Entry("base64url", "${env:SECRET | base64url}", "dXNlcjpwYXNz"),
+		Entry("b64dec", "${prop:encoded | b64dec}", "hello"),
+		Entry("hex", "${env:SECRET | hex}", "757365723a70617373"),
+		Entry("sha256", "${env:SECRET | sha256}", "ef4c914c591698b268db3c64163eafda7209a630f236ebf0eebf045460df723a"),
+		Entry("json", "${prop:quote | json}", `say \"hi\"`),
+		Entry("default for empty values", "${prop:empty | default(none)}", "none"),
+		Entry("default for missing values", "${prop:missing | default(none) | upper}", "NONE"),
+		Entry("default is not used for values", "${prop:name | trim | default(none)}", "Mousse"),
+		Entry("mixed with other tokens", "${prop:name} and ${prop:name | trim | upper}", " Mousse  and MOUSSE"),
+		Entry("unresolved tokens are unchanged", "${prop:missing | upper}", "${prop:missing | upper}"),
+		Entry("unknown filters are not a pipeline", "${prop:name | nope}", "${prop:name | nope}"),
+		Entry("values containing '|' are not a pipeline", "${xpath://a | //b}", "${xpath://a | //b}"),
+		Entry("failed filters are unchanged", "${prop:quote | b64dec}", "${prop:quote | b64dec}"),
+		Entry("tokens for later passes keep their default", "${response:header=Location | default(none)}", "${response:header=Location | default(none)}"),
+		Entry("request tokens keep their default until the request is built", "${request:header=X-Ts | default(0)}", "${request:header=X-Ts | default(0)}"),
+	)
+
+	It("defaults request tokens once the request is built", func() {
+		// Arrange
+		req, _ := http.NewRequest("GET", "http://test.io", nil)
+
+		// Act
+		actual := NewResolver(cfg).WithRequest(req, nil).Resolve("${request:header=X-Ts | default(0)}.${request:method | lower}")
+
+		// Assert
+		Expect(actual).To(Equal("0.get"))
+	})
+
+	It("reports failed filters", func() {
+		// Act
+		actual, err := ResolveFilters("${prop:quote | b64dec}", func(base string, _ bool) (string, bool) { return NewResolver(cfg).Resolve(base), true })
+
+		// Assert
+		Expect(err).To(MatchError(ErrFilterFailed))
+		Expect(err.Error()).To(ContainSubstring("b64dec"))
+		Expect(actual).To(Equal("${prop:quote | b64dec}"))
+	})
+})
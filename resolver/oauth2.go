@@ -0,0 +1,88 @@
+package resolver
+
+import (
+	"fmt"
+
+	"github.com/keithpaterson/postal/cacert"
+	"github.com/keithpaterson/postal/config"
+	"github.com/keithpaterson/postal/oauth2"
+
+	"github.com/keithpaterson/go-tools/resolver"
+	"github.com/keithpaterson/resweave-utils/logging"
+	"go.uber.org/zap"
+)
+
+type oauth2Resolver struct {
+	resolver.ResolverImpl
+
+	log    *zap.SugaredLogger
+	cfg    config.OAuth2Config
+	cacert config.CacertConfig
+	dryRun bool
+
+	// called when a token cannot be fetched, so that the request isn't sent without it
+	onError func(err error)
+}
+
+func newOAuth2Resolver(log *zap.SugaredLogger, cfg *config.Config, onError func(err error)) *oauth2Resolver {
+	return &oauth2Resolver{log: log.Named("oauth2"), cfg: cfg.OAuth2, cacert: cfg.Cacert, dryRun: cfg.Runtime.DryRun, onError: onError}
+}
+
+// accepts "oauth2:access_token" and "oauth2:token_type"
+func (r *oauth2Resolver) Resolve(name string, token string) (string, bool) {
+	if name != "oauth2" || (token != "access_token" && token != "token_type") {
+		return token, false
+	}
+
+	// a dry run doesn't contact the token endpoint
+	if r.dryRun {
+		return token, false
+	}
+
+	value, err := r.fetch()
+	if err != nil {
+		r.log.Errorw("resolve", logging.LogKeyError, err)
+		r.onError(fmt.Errorf("%w: ${oauth2:%s}: %w", ErrResolveFailed, token, err))
+		return token, false
+	}
+
+	if token == "token_type" {
+		return value.TokenType, true
+	}
+	return value.AccessToken, true
+}
+
+func (r *oauth2Resolver) fetch() (*oauth2.Token, error) {
+	// the token endpoint is trusted (or not) just like the request's server
+	tlsConfig, err := cacert.FromConfig(r.resolvedCacert()).TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	return oauth2.NewTokenSource(r.resolvedConfig()).WithTLS(tlsConfig).Token()
+}
+
+func (r *oauth2Resolver) resolvedCacert() config.CacertConfig {
+	cfg := r.cacert
+	cfg.PoolName = r.ResolveValue(cfg.PoolName)
+	cfg.CaCrt = r.ResolveValue(cfg.CaCrt)
+	cfg.Certificates = make([]string, len(r.cacert.Certificates))
+	for index, certificate := range r.cacert.Certificates {
+		cfg.Certificates[index] = r.ResolveValue(certificate)
+	}
+	return cfg
+}
+
+// the oauth2 config is needed before the rest of the config has been resolved, so resolve it here;
+// this allows e.g. client-secret = "${env:CLIENT_SECRET}"
+func (r *oauth2Resolver) resolvedConfig() config.OAuth2Config {
+	cfg := r.cfg
+	for _, value := range []*string{&cfg.TokenURL, &cfg.GrantType, &cfg.ClientID, &cfg.ClientSecret, &cfg.AuthStyle,
+		&cfg.Audience, &cfg.Username, &cfg.Password, &cfg.RefreshToken, &cfg.CacheDir} {
+		*value = r.ResolveValue(*value)
+	}
+	cfg.Scopes = make([]string, len(r.cfg.Scopes))
+	for index, scope := range r.cfg.Scopes {
+		cfg.Scopes[index] = r.ResolveValue(scope)
+	}
+	return cfg
+}
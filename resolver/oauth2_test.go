@@ -0,0 +1,117 @@
+package resolver
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/keithpaterson/postal/config"
+
+	"github.com/keithpaterson/go-tools/env"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OAuth2 Resolver", func() {
+	var (
+		server  *httptest.Server
+		cfg     *config.Config
+		origEnv env.Setup
+		secrets []string
+	)
+	BeforeEach(func() {
+		secrets = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, secret, _ := r.BasicAuth()
+			secrets = append(secrets, secret)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"resolved-token","token_type":"Bearer"}`))
+		}))
+		origEnv = env.New().Set("POSTAL_TEST_SECRET", "from-env").Apply()
+
+		cfg = config.NewConfig()
+		cfg.OAuth2.TokenURL = server.URL
+		cfg.OAuth2.ClientID = "resolver-test"
+		cfg.OAuth2.ClientSecret = "${env:POSTAL_TEST_SECRET}"
+		cfg.OAuth2.NoCache = true
+	})
+	AfterEach(func() {
+		server.Close()
+		origEnv.Apply()
+	})
+
+	DescribeTable("Resolve",
+		func(input string, dryRun bool, expect string) {
+			// Arrange
+			cfg.Runtime.DryRun = dryRun
+
+			// Act
+			actual := NewResolver(cfg).Resolve(input)
+
+			// Assert
+			Expect(actual).To(Equal(expect))
+		},
+		Entry("access token", "Bearer ${oauth2:access_token}", false, "Bearer resolved-token"),
+		Entry("token type", "${oauth2:token_type}", false, "Bearer"),
+		Entry("unsupported value", "${oauth2:id_token}", false, "${oauth2:id_token}"),
+		Entry("dry run does not fetch", "Bearer ${oauth2:access_token}", true, "Bearer ${oauth2:access_token}"),
+	)
+
+	It("resolves tokens in the oauth2 config before fetching", func() {
+		// Act
+		NewResolver(cfg).Resolve("${oauth2:access_token}")
+
+		// Assert
+		Expect(secrets).To(ContainElement("from-env"))
+	})
+
+	It("reports a token that cannot be fetched", func() {
+		// Arrange
+		server.Close()
+		res := NewResolver(cfg)
+
+		// Act
+		actual := res.Resolve("Bearer ${oauth2:access_token}")
+
+		// Assert
+		Expect(actual).To(Equal("Bearer ${oauth2:access_token}"))
+		Expect(res.Err()).To(MatchError(ErrResolveFailed))
+	})
+
+	Context("with a TLS token endpoint", func() {
+		var tlsServer *httptest.Server
+		BeforeEach(func() {
+			tlsServer = httptest.NewTLSServer(server.Config.Handler)
+			cfg.OAuth2.TokenURL = tlsServer.URL
+		})
+		AfterEach(func() {
+			tlsServer.Close()
+		})
+
+		It("trusts the [cacert] certificates", func() {
+			// Arrange
+			crt := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsServer.Certificate().Raw})
+			cfg.Cacert.PoolName = "empty"
+			cfg.Cacert.CaCrt = "string:" + string(crt)
+			res := NewResolver(cfg)
+
+			// Act
+			actual := res.Resolve("${oauth2:access_token}")
+
+			// Assert
+			Expect(res.Err()).ToNot(HaveOccurred())
+			Expect(actual).To(Equal("resolved-token"))
+		})
+
+		It("fails when the certificate is not trusted", func() {
+			// Arrange
+			res := NewResolver(cfg)
+
+			// Act
+			res.Resolve("${oauth2:access_token}")
+
+			// Assert
+			Expect(res.Err()).To(MatchError(ErrResolveFailed))
+		})
+	})
+})
@@ -0,0 +1,68 @@
+package resolver
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/keithpaterson/go-tools/resolver"
+)
+
+type requestResolver struct {
+	resolver.ResolverImpl
+
+	req     *http.Request
+	body    []byte
+	attempt int
+}
+
+// NewRequestResolver returns a resolver for "${request:...}" tokens describing a fully-built request.
+//
+// 'body' is the request body; it is supplied separately because the request body can only be read once.
+func NewRequestResolver(req *http.Request, body []byte) *requestResolver {
+	return &requestResolver{req: req, body: body, attempt: 1}
+}
+
+// WithAttempt sets the value of "${request:attempt}", i.e. how many times the request has been sent (from 1).
+func (r *requestResolver) WithAttempt(attempt int) *requestResolver {
+	r.attempt = attempt
+	return r
+}
+
+func (r *requestResolver) Resolve(name string, token string) (string, bool) {
+	if name != "request" || r.req == nil {
+		return token, false
+	}
+
+	switch strings.ToLower(token) {
+	case "method":
+		return r.req.Method, true
+	case "url":
+		return r.req.URL.String(), true
+	case "path":
+		return r.req.URL.EscapedPath(), true
+	case "query":
+		return r.req.URL.RawQuery, true
+	case "host":
+		return r.host(), true
+	case "body":
+		return string(r.body), true
+	case "content-length", "contentlength":
+		return strconv.Itoa(len(r.body)), true
+	case "attempt":
+		return strconv.Itoa(r.attempt), true
+	}
+
+	// header is a special case..
+	if name, ok := strings.CutPrefix(token, "header="); ok && name != "" {
+		return strings.Join(r.req.Header.Values(name), ","), true
+	}
+	return token, false
+}
+
+func (r *requestResolver) host() string {
+	if r.req.Host != "" {
+		return r.req.Host
+	}
+	return r.req.URL.Host
+}
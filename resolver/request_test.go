@@ -0,0 +1,53 @@
+package resolver
+
+import (
+	"net/http"
+
+	"github.com/keithpaterson/postal/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Request Resolver", func() {
+	var (
+		req  *http.Request
+		body = []byte(`{"name":"test"}`)
+	)
+	BeforeEach(func() {
+		var err error
+		req, err = http.NewRequest("PUT", "https://test.io/foo/bar?this=that", nil)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Add("X-Test", "one")
+		req.Header.Add("X-Test", "two")
+	})
+
+	DescribeTable("Resolve",
+		func(input string, expect string) {
+			// Act
+			actual := NewResolver(config.NewConfig()).WithRequest(req, body).Resolve(input)
+
+			// Assert
+			Expect(actual).To(Equal(expect))
+		},
+		Entry("method", "${request:method}", "PUT"),
+		Entry("url", "${request:url}", "https://test.io/foo/bar?this=that"),
+		Entry("path", "${request:path}", "/foo/bar"),
+		Entry("query", "${request:query}", "this=that"),
+		Entry("host", "${request:host}", "test.io"),
+		Entry("body", "${request:body}", `{"name":"test"}`),
+		Entry("content-length", "${request:content-length}", "15"),
+		Entry("header", "${request:header=X-Test}", "one,two"),
+		Entry("attempt", "${request:attempt}", "1"),
+		Entry("missing header", "[${request:header=X-Missing}]", "[]"),
+		Entry("unsupported value", "${request:foo}", "${request:foo}"),
+	)
+
+	It("is not available without a request", func() {
+		// Act
+		actual := NewResolver(config.NewConfig()).Resolve("${request:method}")
+
+		// Assert
+		Expect(actual).To(Equal("${request:method}"))
+	})
+})
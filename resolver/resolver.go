@@ -1,25 +1,86 @@
 package resolver
 
 import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
 	"github.com/keithpaterson/postal/config"
 
 	"github.com/keithpaterson/go-tools/resolver"
 	"github.com/keithpaterson/postal/logging"
+	ulog "github.com/keithpaterson/resweave-utils/logging"
 	"go.uber.org/zap"
 )
 
+var (
+	ErrResolveFailed = errors.New("failed to resolve token")
+)
+
+// the types of token that are always resolved; see Resolve()
+var resolverNames = []string{"env", "prop", "date", "time", "datetime", "epoch", "jwt", "oauth2"}
+
 type wrapResolver struct {
 	log *zap.SugaredLogger
 	cfg *config.Config
+
+	// optional; only available once the request has been built
+	request *requestResolver
+
+	// tokens that failed to resolve, when that means the request must not be sent
+	errs []error
 }
 
 func NewResolver(cfg *config.Config) *wrapResolver {
 	return &wrapResolver{log: logging.NamedLogger("resolver"), cfg: cfg}
 }
 
+// WithRequest enables "${request:...}" tokens, which describe the fully-built request.
+func (r *wrapResolver) WithRequest(req *http.Request, body []byte) *wrapResolver {
+	r.request = NewRequestResolver(req, body)
+	return r
+}
+
 func (r *wrapResolver) Resolve(input string) string {
-	return resolver.NewResolver(&resolver.ResolverConfig{Properties: resolver.Properties(r.cfg.Properties)}).
+	root := resolver.NewResolver(&resolver.ResolverConfig{Properties: resolver.Properties(r.cfg.Properties)}).
 		WithStandardResolvers().
 		WithResolver("jwt", newJWTResolver(r.log, r.cfg.JWT)).
-		Resolve(input)
+		WithResolver("oauth2", newOAuth2Resolver(r.log, r.cfg, func(err error) { r.errs = append(r.errs, err) }))
+	if r.request != nil {
+		root.WithResolver("request", r.request)
+	}
+
+	// filters first; otherwise the resolvers would try to resolve the whole pipeline
+	input, err := ResolveFilters(input, func(base string, _ bool) (string, bool) {
+		if !r.canResolve(base) {
+			// e.g. ${response:...} tokens; keep the filters (and any default) for when they can be resolved
+			return base, false
+		}
+		return root.Resolve(base), true
+	})
+	if err != nil {
+		r.log.Errorw("resolve", ulog.LogKeyError, err)
+	}
+	return root.Resolve(input)
+}
+
+// Err returns the errors for tokens that could not be resolved and that the request cannot do without
+// (e.g. ${oauth2:access_token} when the token endpoint fails).
+func (r *wrapResolver) Err() error {
+	return errors.Join(r.errs...)
+}
+
+// canResolve reports whether a resolver is registered for the type of token ("${type:value}");
+// tokens without a type are properties.
+func (r *wrapResolver) canResolve(token string) bool {
+	name, _, ok := strings.Cut(strings.TrimSuffix(strings.TrimPrefix(token, "${"), "}"), ":")
+	if !ok {
+		return true
+	}
+	name = strings.ToLower(name)
+	if name == "request" {
+		return r.request != nil
+	}
+	return slices.Contains(resolverNames, name)
 }
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/keithpaterson/postal/assertion"
+	"github.com/keithpaterson/postal/capture"
 	"github.com/keithpaterson/postal/config"
+	"github.com/keithpaterson/postal/redact"
 )
 
 func (s *httpSender) dryRun(req *http.Request) error {
@@ -12,12 +15,26 @@ func (s *httpSender) dryRun(req *http.Request) error {
 	fmt.Println("DRY RUN")
 	fmt.Println("-------")
 	fmt.Println("\nConfiguration:")
-	s.dryCfgRequest(s.cfg.Request)
-	s.dryCacert(s.cfg.Cacert)
-	s.dryProperties(s.cfg.Properties)
-	s.dryOutput(s.cfg.Output)
 
-	s.dryHttpRequest(req)
+	// never print secrets unless explicitly asked to
+	redactor := redact.New(s.cfg)
+	cfg := redactor.Config(s.cfg)
+	s.dryCfgRequest(cfg.Request)
+	s.dryCacert(cfg.Cacert)
+	s.dryOAuth2(cfg.OAuth2)
+	s.dryAuth(cfg.Auth)
+	s.drySigning(cfg.Signing)
+	s.dryProperties(cfg.Properties)
+	s.dryOutput(cfg.Output)
+	s.dryDownload(cfg.Download)
+	s.dryCapture(cfg.Capture, cfg.Output.CaptureFile)
+	s.dryAssert(cfg.Assert)
+
+	if req != nil {
+		masked := *req
+		masked.Header = redactor.Headers(req.Header)
+		s.dryHttpRequest(&masked)
+	}
 
 	fmt.Println()
 
@@ -35,7 +52,7 @@ func (s *httpSender) dryCfgRequest(req config.RequestConfig) {
 
 	if len(req.Headers) > 0 {
 		fmt.Print("    with headers:\n      ")
-		for key, value := range s.cfg.Request.Headers {
+		for key, value := range req.Headers {
 			fmt.Printf("%s=%s; ", key, value)
 		}
 	}
@@ -64,6 +81,51 @@ func (s *httpSender) dryCacert(cacert config.CacertConfig) {
 	}
 }
 
+func (s *httpSender) dryOAuth2(oauth2 config.OAuth2Config) {
+	if oauth2.TokenURL == "" {
+		return
+	}
+
+	fmt.Println("  OAuth2:")
+	fmt.Println("    Token URL:", oauth2.TokenURL)
+	fmt.Println("    Grant:", oauth2.GrantType)
+	if oauth2.ClientID != "" {
+		fmt.Println("    Client ID:", oauth2.ClientID)
+	}
+	if len(oauth2.Scopes) > 0 {
+		fmt.Println("    Scopes:", oauth2.Scopes)
+	}
+	if oauth2.Audience != "" {
+		fmt.Println("    Audience:", oauth2.Audience)
+	}
+}
+
+func (s *httpSender) dryAuth(auth config.AuthConfig) {
+	if auth.AuthType() == config.AuthNone {
+		return
+	}
+
+	fmt.Println("  Auth:")
+	fmt.Println("    Type:", auth.Type)
+	fmt.Println("    Region:", auth.Region)
+	fmt.Println("    Service:", auth.Service)
+	fmt.Println("    Access Key:", auth.AccessKey)
+}
+
+func (s *httpSender) drySigning(signing config.SigningConfig) {
+	if signing.Header == "" {
+		return
+	}
+
+	fmt.Println("  Signing:")
+	fmt.Println("    Header:", signing.Header)
+	fmt.Println("    Algorithm:", signing.Algorithm, "encoded as", signing.Encoding)
+	fmt.Println("    using Template:")
+	fmt.Println("      >>>")
+	fmt.Println(signing.Template)
+	fmt.Println("      <<<")
+}
+
 func (s *httpSender) dryProperties(props config.Properties) {
 	if len(props) == 0 {
 		return
@@ -78,10 +140,36 @@ func (s *httpSender) dryOutput(output config.OutputConfig) {
 	fmt.Println("  Output:")
 	fmt.Println("    Format:", output.Format)
 	fmt.Println("    Filename:", output.Filename)
-	fmt.Println("    using Template:")
+	fmt.Println("    using Template (engine: " + output.TemplateEngine().String() + "):")
 	fmt.Println("      >>>")
 	fmt.Println(output.Template)
 	fmt.Println("      <<<")
+	if output.FailOn != "" {
+		fmt.Println("    Fail On:", output.FailOn)
+	}
+	if output.Report != "" {
+		fmt.Println("    Report:", output.Report)
+	}
+
+	if len(output.Sinks) > 0 {
+		fmt.Println("    Sinks (these replace the Filename):")
+		for _, sink := range output.Sinks {
+			sink := output.ForSink(sink)
+			fmt.Printf("      %s -> %s (append: %v): %s\n", sink.Format, sink.Filename, sink.Append, sink.Template)
+		}
+	}
+}
+
+func (s *httpSender) dryDownload(download config.DownloadConfig) {
+	if download.Path == "" {
+		return
+	}
+
+	fmt.Println("  Download:")
+	fmt.Println("    Path:", download.Path)
+	if download.ExpectedSHA256 != "" {
+		fmt.Println("    Expected SHA-256:", download.ExpectedSHA256)
+	}
 }
 
 func (s *httpSender) dryHttpRequest(req *http.Request) {
@@ -100,3 +188,26 @@ func (s *httpSender) dryHttpRequest(req *http.Request) {
 	}
 	fmt.Println("  Content Length:", req.ContentLength)
 }
+
+func (s *httpSender) dryCapture(captures []config.CaptureConfig, filename string) {
+	if len(captures) == 0 {
+		return
+	}
+	if filename == "" {
+		filename = capture.DefaultFile
+	}
+	fmt.Println("  Capture (into " + filename + "):")
+	for _, c := range captures {
+		fmt.Printf("    %s = %s %s\n", c.Name, c.From, c.Expr)
+	}
+}
+
+func (s *httpSender) dryAssert(asserts []config.AssertConfig) {
+	if len(asserts) == 0 {
+		return
+	}
+	fmt.Println("  Assert:")
+	for _, a := range asserts {
+		fmt.Println("   ", assertion.Description(a))
+	}
+}
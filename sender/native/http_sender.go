@@ -2,16 +2,24 @@ package native
 
 import (
 	"bytes"
-	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/keithpaterson/postal/assertion"
+	"github.com/keithpaterson/postal/auth"
 	"github.com/keithpaterson/postal/cacert"
+	"github.com/keithpaterson/postal/capture"
 	"github.com/keithpaterson/postal/config"
+	"github.com/keithpaterson/postal/download"
 	"github.com/keithpaterson/postal/output"
+	"github.com/keithpaterson/postal/report"
+	"github.com/keithpaterson/postal/statusmatch"
+	"github.com/keithpaterson/postal/trace"
 	"github.com/keithpaterson/postal/validate"
 
 	"github.com/keithpaterson/resweave-utils/client"
@@ -25,11 +33,25 @@ var (
 	ErrUnsupportedBodySpec = errors.New("unsupported Request.Body spec")
 	ErrInvalidBody         = errors.New("invalid body")
 	ErrInvalidCert         = errors.New("invalid TLS certificate")
+
+	// these identify which stage of sending the request failed
+	ErrInvalidRequest = errors.New("failed to build request")
+	ErrRequestFailed  = errors.New("request failed")
+	ErrOutputFailed   = errors.New("failed to write response")
+	ErrStatusFailed   = errors.New("response status indicates failure")
+	ErrAssertFailed   = errors.New("assertions failed")
 )
 
 type httpSender struct {
 	cfg *config.Config
 	log *zap.SugaredLogger
+
+	// saves the body instead of writing output, when a download is configured
+	downloader *download.Downloader
+
+	// the outcome, for the report
+	status  string
+	results []assertion.Result
 }
 
 func sendHttp(cfg *config.Config, log *zap.SugaredLogger) error {
@@ -44,42 +66,80 @@ func (s *httpSender) execute() error {
 	var err error
 	var body []byte
 	if body, err = s.getBodyData(); err != nil {
-		return err
+		return fmt.Errorf("%w: %w", ErrInvalidRequest, err)
 	}
 
 	var req *http.Request
 	if req, err = s.newRequest(body); err != nil {
-		return err
+		return fmt.Errorf("%w: %w", ErrInvalidRequest, err)
 	}
 	for key, value := range s.cfg.Request.Headers {
 		req.Header.Add(key, value)
 	}
 
+	if s.cfg.Download.Path != "" {
+		// may ask the server to resume a partial download
+		s.downloader = download.New(s.cfg.Download)
+		s.downloader.Prepare(req)
+	}
+
+	// signing must be the last thing that happens to the request before it is sent
+	if err = s.signRequest(req, body); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+
 	if s.cfg.Runtime.DryRun {
 		return s.dryRun(req)
 	}
 
-	return s.sendAndReceive(req)
+	start := time.Now()
+	err = s.sendAndReceive(req)
+	return s.report(req, time.Since(start), err)
+}
+
+// report writes the test report (if one is configured); err is the outcome of sending the request
+func (s *httpSender) report(req *http.Request, duration time.Duration, err error) error {
+	if s.cfg.Output.Report == "" {
+		return err
+	}
+	run := report.Run{
+		Request:  req.Method + " " + req.URL.Redacted(),
+		Status:   s.status,
+		Duration: duration,
+		Results:  s.results,
+	}
+	// failed assertions are already in the results
+	if err != nil && !errors.Is(err, ErrAssertFailed) {
+		run.Err = err
+	}
+	if reportErr := report.Write(s.cfg.Output.Report, run, s.cfg.Output.Append); reportErr != nil {
+		return errors.Join(err, fmt.Errorf("%w: %w", ErrOutputFailed, reportErr))
+	}
+	return err
 }
 
 func (s *httpSender) configureTLS(client *http.Client) error {
-	if s.cfg.Cacert.Pool() != config.CertPoolNone {
-		parser := cacert.FromConfig(s.cfg.Cacert)
-		pool, err := parser.GetCertificatePool()
-		if err != nil {
-			s.log.Errorw("execute", ulog.LogKeyStatus, "failed to build cert pool", ulog.LogKeyError, err)
-			return fmt.Errorf("%w: invalid pool: %w", ErrInvalidCert, err)
-		}
-		certificates, err := parser.GetCertificates()
-		if err != nil {
-			s.log.Errorw("execute", ulog.LogKeyStatus, "failed to build certificate list", ulog.LogKeyError, err)
-			return fmt.Errorf("failed to configure TLS: %w", err)
-		}
-		client.Transport = &http.Transport{
-			TLSClientConfig: &tls.Config{
-				RootCAs:      pool,
-				Certificates: certificates,
-			},
+	tlsConfig, err := cacert.FromConfig(s.cfg.Cacert).TLSConfig()
+	if err != nil {
+		s.log.Errorw("execute", ulog.LogKeyStatus, "failed to configure TLS", ulog.LogKeyError, err)
+		return fmt.Errorf("%w: %w", ErrInvalidCert, err)
+	}
+	if tlsConfig != nil {
+		client.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+	}
+	return nil
+}
+
+// the HMAC signature header is added first so that it is included in the auth signature
+func (s *httpSender) signRequest(req *http.Request, body []byte) error {
+	signer, err := auth.NewSigner(s.cfg.Auth)
+	if err != nil {
+		return err
+	}
+	for _, signer := range []auth.Signer{auth.NewHMACSigner(s.cfg), signer} {
+		if err = signer.Sign(req, body); err != nil {
+			s.log.Errorw("execute", ulog.LogKeyStatus, "failed to sign request", ulog.LogKeyError, err)
+			return err
 		}
 	}
 	return nil
@@ -130,20 +190,109 @@ func (s *httpSender) getBodyData() ([]byte, error) {
 func (s *httpSender) sendAndReceive(req *http.Request) error {
 	var err error
 	var resp *http.Response
-	c := client.NewHTTPClient("test").WithRetryHandler(client.NewRetryCounter(0))
+	c := client.NewHTTPClient("test").WithRetryHandler(client.NewRetryCounter(0)).WithBackoff(noBackoff{})
+	// use our own client rather than modifying http.DefaultClient
+	c.Client = &http.Client{}
 	if err = s.configureTLS(c.Client); err != nil {
-		return err
+		return fmt.Errorf("%w: %w", ErrInvalidRequest, err)
 	}
+	// record every round trip (including redirects) for the outputters
+	c.Client.Transport = trace.NewTransport(c.Client.Transport)
 
-	if resp, err = c.Execute(req); err != nil {
-		return err
+	t := trace.New()
+	if resp, err = c.Execute(trace.WithTrace(req, t)); err != nil {
+		return fmt.Errorf("%w: %w", ErrRequestFailed, err)
 	}
 	defer resp.Body.Close()
+	t.MarkResponse()
+	s.status = resp.Status
 
-	writer := output.NewOutputter(s.cfg)
-	if err = writer.Write(resp); err != nil {
+	var body []byte
+	if (len(s.cfg.Capture) > 0 || len(s.cfg.Assert) > 0) && s.downloader == nil {
+		// captures and assertions need the body after the outputter has read it
+		if body, err = io.ReadAll(resp.Body); err != nil {
+			return fmt.Errorf("%w: %w", ErrRequestFailed, err)
+		}
+		t.MarkDone()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if s.downloader != nil {
+		// the status is checked first, so that an error page isn't saved as (or appended to) the file
+		if err = s.checkStatus(resp); err != nil {
+			return err
+		}
+		if err = s.downloader.Write(resp); err != nil {
+			return fmt.Errorf("%w: %w", ErrOutputFailed, err)
+		}
+	} else {
+		if err = output.NewOutputter(s.cfg).Write(resp); err != nil {
+			return fmt.Errorf("%w: %w", ErrOutputFailed, err)
+		}
+		// the output is always written, so that failed responses can be inspected
+		if err = s.checkStatus(resp); err != nil {
+			return err
+		}
+	}
+	if err = s.assert(resp, body); err != nil {
 		return err
 	}
+	return s.capture(resp, body)
+}
 
+// assert writes the assertion report to stderr, so that it doesn't mix with the output
+func (s *httpSender) assert(resp *http.Response, body []byte) error {
+	if len(s.cfg.Assert) == 0 {
+		return nil
+	}
+	summary := assertion.Evaluate(s.cfg.Assert, resp, body)
+	s.results = summary.Results
+	summary.Write(os.Stderr)
+	if failed := summary.Failed(); failed > 0 {
+		return fmt.Errorf("%w: %d of %d", ErrAssertFailed, failed, len(summary.Results))
+	}
 	return nil
 }
+
+func (s *httpSender) capture(resp *http.Response, body []byte) error {
+	if len(s.cfg.Capture) == 0 {
+		return nil
+	}
+	props, err := capture.Values(s.cfg.Capture, resp, body)
+	if err == nil {
+		err = capture.Save(s.cfg.Output.CaptureFile, props)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrOutputFailed, err)
+	}
+	return nil
+}
+
+func (s *httpSender) checkStatus(resp *http.Response) error {
+	if s.cfg.Output.FailOn == "" {
+		return nil
+	}
+	matcher, err := statusmatch.Parse(s.cfg.Output.FailOn)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+	if matcher.Match(resp.StatusCode) {
+		return fmt.Errorf("%w: '%s' matches fail-on '%s'", ErrStatusFailed, resp.Status, s.cfg.Output.FailOn)
+	}
+	return nil
+}
+
+// noBackoff is used because requests are never retried: the client still "backs off" once before giving up
+// after a transport error, which panics with a zero-length static backoff.
+// A zero Timeout leaves the http client without a timeout.
+type noBackoff struct{}
+
+func (noBackoff) Reset()                 {}
+func (noBackoff) Timeout() time.Duration { return 0 }
+func (noBackoff) Advance() time.Duration { return 0 }
+func (noBackoff) Stop()                  {}
+func (noBackoff) Start() <-chan time.Time {
+	c := make(chan time.Time, 1)
+	c <- time.Now()
+	return c
+}
@@ -1,11 +1,14 @@
 package native
 
 import (
-	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/keithpaterson/postal/auth"
 	"github.com/keithpaterson/postal/cacert"
 	"github.com/keithpaterson/postal/config"
 	"github.com/keithpaterson/postal/logging"
@@ -84,11 +87,174 @@ var _ = Describe("Native Sender", func() {
 					Expect(err).ToNot(HaveOccurred())
 				}
 			},
-			Entry("bad method", requestCfg("not valid", "none", `json:{"name":"test"}`), errors.New(`net/http: invalid method "NOT VALID"`)),
+			Entry("bad method", requestCfg("not valid", "none", `json:{"name":"test"}`), ErrInvalidRequest),
 			Entry("bad json", requestCfg("get", "none", `json:this is not json`), ErrInvalidBody),
 			Entry("invalid cert", requestCfg("get", "invalid pool", `json:{"name":"test"}`), cacert.ErrInvalidPool),
+			Entry("invalid auth", withAuth(requestCfg("get", "none", ""), config.AuthConfig{Type: "invalid"}), auth.ErrInvalidAuthType),
+			Entry("missing auth credentials", withAuth(requestCfg("get", "none", ""), config.AuthConfig{Type: "aws-sigv4"}), auth.ErrMissingCredentials),
 		)
 
+		DescribeTable("fail on",
+			func(status int, failOn string, expect error) {
+				// Arrange
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(status)
+				}))
+				defer server.Close()
+
+				cfg := requestCfg("get", "none", "")
+				cfg.Request.URL = server.URL
+				cfg.Output.Filename = os.DevNull
+				cfg.Output.FailOn = failOn
+
+				// Act
+				err := sendHttp(cfg, logging.NamedLogger("test"))
+
+				// Assert
+				if expect != nil {
+					Expect(err).To(MatchError(expect))
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+				}
+			},
+			Entry("not configured", http.StatusInternalServerError, "", nil),
+			Entry("no match", http.StatusNotFound, "5xx", nil),
+			Entry("match", http.StatusInternalServerError, ">=400", ErrStatusFailed),
+			Entry("negated match", http.StatusNoContent, "!200", ErrStatusFailed),
+		)
+
+		It("captures values", func() {
+			// Arrange
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"id":"ord-42"}`))
+			}))
+			defer server.Close()
+
+			cfg := requestCfg("get", "none", "")
+			cfg.Request.URL = server.URL
+			cfg.Output.Filename = os.DevNull
+			cfg.Output.CaptureFile = filepath.Join(GinkgoT().TempDir(), "captured.toml")
+			cfg.Capture = []config.CaptureConfig{{Name: "order_id", From: "json", Expr: "id"}}
+
+			// Act
+			err := sendHttp(cfg, logging.NamedLogger("test"))
+
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			data, err := os.ReadFile(cfg.Output.CaptureFile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring(`order_id = "ord-42"`))
+		})
+
+		DescribeTable("assertions",
+			func(asserts []config.AssertConfig, expect error) {
+				// Arrange
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte(`{"id":"ord-42"}`))
+				}))
+				defer server.Close()
+
+				cfg := requestCfg("get", "none", "")
+				cfg.Request.URL = server.URL
+				cfg.Output.Filename = os.DevNull
+				cfg.Assert = asserts
+
+				// Act
+				err := sendHttp(cfg, logging.NamedLogger("test"))
+
+				// Assert
+				if expect != nil {
+					Expect(err).To(MatchError(expect))
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+				}
+			},
+			Entry("pass", []config.AssertConfig{{Check: "status", Expr: "200"}, {Check: "json", Expr: "id", Contains: "ord"}}, nil),
+			Entry("fail", []config.AssertConfig{{Check: "status", Expr: "200"}, {Check: "json", Expr: "id", Contains: "nope"}}, ErrAssertFailed),
+		)
+
+		It("writes a report", func() {
+			// Arrange
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"id":"ord-42"}`))
+			}))
+			defer server.Close()
+
+			cfg := requestCfg("get", "none", "")
+			cfg.Request.URL = server.URL
+			cfg.Output.Filename = os.DevNull
+			filename := filepath.Join(GinkgoT().TempDir(), "results.tap")
+			cfg.Output.Report = "tap:" + filename
+			cfg.Assert = []config.AssertConfig{{Check: "status", Expr: "200"}, {Name: "order id", Check: "json", Expr: "id", Equals: &[]string{"nope"}[0]}}
+
+			// Act
+			err := sendHttp(cfg, logging.NamedLogger("test"))
+
+			// Assert
+			Expect(err).To(MatchError(ErrAssertFailed))
+			data, err := os.ReadFile(filename)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(HavePrefix("TAP version 13\n1..2\nok 1 - status 200\nnot ok 2 - order id\n"))
+			Expect(string(data)).To(ContainSubstring(`request: "GET ` + server.URL + `"`))
+		})
+
+		It("checks the status before saving a download", func() {
+			// Arrange
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.NotFound(w, r)
+			}))
+			defer server.Close()
+
+			path := filepath.Join(GinkgoT().TempDir(), "file.bin")
+			cfg := requestCfg("get", "none", "")
+			cfg.Request.URL = server.URL
+			cfg.Output.FailOn = ">=400"
+			cfg.Download = config.DownloadConfig{Path: path, NoProgress: true}
+
+			// Act
+			err := sendHttp(cfg, logging.NamedLogger("test"))
+
+			// Assert
+			Expect(err).To(MatchError(ErrStatusFailed))
+			Expect(path).ToNot(BeAnExistingFile())
+			Expect(path + ".part").ToNot(BeAnExistingFile())
+		})
+
+		It("identifies transport errors", func() {
+			// Arrange
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			cfg := requestCfg("get", "none", "")
+			cfg.Request.URL = server.URL
+			server.Close()
+
+			// Act
+			err := sendHttp(cfg, logging.NamedLogger("test"))
+
+			// Assert
+			Expect(err).To(MatchError(ErrRequestFailed))
+		})
+
+		It("signs the request", func() {
+			// Arrange
+			var authorization string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authorization = r.Header.Get("Authorization")
+			}))
+			defer server.Close()
+
+			cfg := requestCfg("get", "none", "")
+			cfg.Request.URL = server.URL
+			cfg.Auth = config.AuthConfig{Type: "aws-sigv4", Region: "us-east-1", Service: "s3", AccessKey: "access", SecretKey: "secret"}
+			cfg.Output.Filename = os.DevNull
+
+			// Act
+			err := sendHttp(cfg, logging.NamedLogger("test"))
+
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(authorization).To(HavePrefix("AWS4-HMAC-SHA256 Credential=access/"))
+		})
+
 		It("succeeds with valid data", func() {
 			// Arrange
 			host, tearDown := test.HttpService().
@@ -122,3 +288,8 @@ func requestCfg(method string, certPool string, body string) *config.Config {
 	cfg.Cacert.PoolName = certPool
 	return cfg
 }
+
+func withAuth(cfg *config.Config, auth config.AuthConfig) *config.Config {
+	cfg.Auth = auth
+	return cfg
+}
@@ -26,13 +26,13 @@ func (s *sender) Send(cfg *config.Config) error {
 	// parse the URL here so that we can determine the scheme; from that we can call an appropriate function to handle that scheme
 	var target *url.URL
 	if target, err = url.Parse(s.cfg.Request.URL); err != nil {
-		return err
+		return fmt.Errorf("%w: %w", ErrInvalidRequest, err)
 	}
 	switch target.Scheme {
 	case "http", "https":
 		err = sendHttp(s.cfg, s.log)
 	default:
-		err = fmt.Errorf("unsupported scheme '%s'", target.Scheme)
+		err = fmt.Errorf("%w: unsupported scheme '%s'", ErrInvalidRequest, target.Scheme)
 	}
 	return err
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/keithpaterson/postal/config"
 	"github.com/keithpaterson/postal/logging"
+	"github.com/keithpaterson/postal/redact"
 	"github.com/keithpaterson/postal/sender/curl"
 	"github.com/keithpaterson/postal/sender/native"
 	"github.com/keithpaterson/postal/validate"
@@ -29,6 +30,7 @@ const (
 
 var (
 	ErrInvalidSender = errors.New("invalid sender")
+	ErrInvalidConfig = errors.New("invalid configuration")
 )
 
 var (
@@ -63,13 +65,13 @@ func (s *rootSender) Send(cfg *config.Config) error {
 	var err error
 	var actualCfg *config.Config
 	if actualCfg, err = validate.ValidateConfig(cfg); err != nil {
-		return err
+		return fmt.Errorf("%w: %w", ErrInvalidConfig, err)
 	}
-	s.log.Debugw("Send", "validated config", fmt.Sprintf("%#v", actualCfg))
-
 	// Runtime info doesn't get persisted, so copy the original information
 	actualCfg.Runtime = cfg.Runtime
 
+	s.log.Debugw("Send", "validated config", fmt.Sprintf("%#v", redact.New(actualCfg).Config(actualCfg)))
+
 	switch s.id {
 	case NativeSender:
 		e := native.NewSender(s.log)
@@ -0,0 +1,114 @@
+// package statusmatch matches HTTP status codes against specifications such as ">=400", "4xx" or "500-599".
+package statusmatch
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidSpec = errors.New("invalid status specification")
+)
+
+// Matcher matches a status code if any of its rules match.
+type Matcher []rule
+
+type rule struct {
+	min, max int
+	negate   bool
+}
+
+// Parse parses a comma-separated list of rules, where each rule is one of:
+//   - an exact status code, e.g. "404"
+//   - a status class, e.g. "4xx"
+//   - a range of status codes, e.g. "500-599"
+//   - a comparison, one of ">=", ">", "<=", "<" followed by a status code, e.g. ">=400"
+//
+// Any rule can be negated using "!", e.g. "!2xx" matches everything that is not a 2xx status.
+func Parse(spec string) (Matcher, error) {
+	var matcher Matcher
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		r, err := parseRule(part)
+		if err != nil {
+			return nil, err
+		}
+		matcher = append(matcher, r)
+	}
+	if len(matcher) == 0 {
+		return nil, fmt.Errorf("%w: '%s' is empty", ErrInvalidSpec, spec)
+	}
+	return matcher, nil
+}
+
+// Match returns true if the status code matches any of the rules.
+func (m Matcher) Match(code int) bool {
+	for _, r := range m {
+		if (code >= r.min && code <= r.max) != r.negate {
+			return true
+		}
+	}
+	return false
+}
+
+func parseRule(spec string) (rule, error) {
+	var r rule
+	text, negate := strings.CutPrefix(spec, "!")
+	r.negate = negate
+	text = strings.TrimSpace(text)
+
+	var err error
+	switch {
+	case strings.HasPrefix(text, ">="):
+		r.min, err = parseCode(text[2:])
+		r.max = 999
+	case strings.HasPrefix(text, ">"):
+		r.min, err = parseCode(text[1:])
+		r.min++
+		r.max = 999
+	case strings.HasPrefix(text, "<="):
+		r.max, err = parseCode(text[2:])
+	case strings.HasPrefix(text, "<"):
+		r.max, err = parseCode(text[1:])
+		r.max--
+	case len(text) == 3 && strings.EqualFold(text[1:], "xx"):
+		var class int
+		if class, err = strconv.Atoi(text[:1]); err == nil && class >= 1 && class <= 5 {
+			r.min, r.max = class*100, class*100+99
+		} else {
+			err = errors.New("unknown class")
+		}
+	case strings.Contains(text, "-"):
+		low, high, _ := strings.Cut(text, "-")
+		if r.min, err = parseCode(low); err == nil {
+			r.max, err = parseCode(high)
+		}
+		if err == nil && r.min > r.max {
+			err = errors.New("empty range")
+		}
+	default:
+		r.min, err = parseCode(text)
+		r.max = r.min
+	}
+
+	if err != nil {
+		return rule{}, fmt.Errorf("%w: '%s': %w", ErrInvalidSpec, spec, err)
+	}
+	return r, nil
+}
+
+func parseCode(text string) (int, error) {
+	code, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil {
+		return 0, errors.New("not a number")
+	}
+	if code < 100 || code > 999 {
+		return 0, errors.New("not a status code")
+	}
+	return code, nil
+}
@@ -0,0 +1,51 @@
+package statusmatch
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Matcher", func() {
+	DescribeTable("Match",
+		func(spec string, matches []int, misses []int) {
+			// Arrange
+			matcher, err := Parse(spec)
+			Expect(err).ToNot(HaveOccurred())
+
+			// Act & Assert
+			for _, code := range matches {
+				Expect(matcher.Match(code)).To(BeTrue(), "expected %d to match '%s'", code, spec)
+			}
+			for _, code := range misses {
+				Expect(matcher.Match(code)).To(BeFalse(), "expected %d not to match '%s'", code, spec)
+			}
+		},
+		Entry("exact", "404", []int{404}, []int{400, 405}),
+		Entry("class", "5xx", []int{500, 503, 599}, []int{499, 600}),
+		Entry("class is case-insensitive", "4XX", []int{404}, []int{200}),
+		Entry("range", "500-502", []int{500, 502}, []int{503}),
+		Entry("greater or equal", ">=400", []int{400, 503}, []int{399}),
+		Entry("greater", ">400", []int{401}, []int{400}),
+		Entry("less or equal", "<=299", []int{200, 299}, []int{300}),
+		Entry("less", "<300", []int{299}, []int{300}),
+		Entry("negated", "!2xx", []int{199, 300, 500}, []int{200, 204}),
+		Entry("list", "404, 5xx", []int{404, 500}, []int{400, 200}),
+	)
+
+	DescribeTable("invalid specifications",
+		func(spec string) {
+			// Act
+			_, err := Parse(spec)
+
+			// Assert
+			Expect(err).To(MatchError(ErrInvalidSpec))
+		},
+		Entry("empty", ""),
+		Entry("only commas", " , "),
+		Entry("not a number", "abc"),
+		Entry("not a status code", "42"),
+		Entry("unknown class", "7xx"),
+		Entry("empty range", "500-400"),
+		Entry("bad comparison", ">=x"),
+	)
+})
@@ -0,0 +1,11 @@
+/*
+package trace records what happened during a single request/response exchange.
+
+The [Trace] is attached to the request's context before it is sent, which lets the
+[output] package find it again via the response (response.Request.Context()) without
+having to thread extra state through the outputters.
+
+Wrap the client's transport with [NewTransport] to also record every round trip
+(including each redirect hop) in the trace.
+*/
+package trace
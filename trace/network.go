@@ -0,0 +1,69 @@
+package trace
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"time"
+)
+
+// networkTimes accumulates the httptrace events for every round trip in the exchange
+type networkTimes struct {
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+
+	dns     time.Duration
+	connect time.Duration
+	tls     time.Duration
+
+	// the most recent request, so that redirects report the final response
+	wroteRequest time.Time
+	firstByte    time.Time
+
+	// the address of the most recent connection
+	remoteAddr string
+}
+
+func (t *Trace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.record(func(n *networkTimes, now time.Time) { n.dnsStart = now })
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.record(func(n *networkTimes, now time.Time) { n.dns += between(n.dnsStart, now) })
+		},
+		ConnectStart: func(string, string) {
+			t.record(func(n *networkTimes, now time.Time) { n.connectStart = now })
+		},
+		ConnectDone: func(string, string, error) {
+			t.record(func(n *networkTimes, now time.Time) { n.connect += between(n.connectStart, now) })
+		},
+		TLSHandshakeStart: func() {
+			t.record(func(n *networkTimes, now time.Time) { n.tlsStart = now })
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.record(func(n *networkTimes, now time.Time) { n.tls += between(n.tlsStart, now) })
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Conn == nil {
+				return
+			}
+			addr := info.Conn.RemoteAddr().String()
+			t.record(func(n *networkTimes, _ time.Time) { n.remoteAddr = addr })
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			t.record(func(n *networkTimes, now time.Time) { n.wroteRequest = now; n.firstByte = time.Time{} })
+		},
+		GotFirstResponseByte: func() {
+			t.record(func(n *networkTimes, now time.Time) { n.firstByte = now })
+		},
+	}
+}
+
+// the hooks can be called from the transport's goroutines
+func (t *Trace) record(update func(n *networkTimes, now time.Time)) {
+	now := time.Now()
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	update(&t.network, now)
+}
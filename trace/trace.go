@@ -0,0 +1,149 @@
+package trace
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+type traceKey struct{}
+
+// Trace holds timing information and the round trips for a single exchange.
+type Trace struct {
+	// time the request was sent
+	Start time.Time
+	// time the response headers were received
+	Response time.Time
+	// time the response body was fully read
+	Done time.Time
+
+	mutex   sync.Mutex
+	hops    []Hop
+	network networkTimes
+}
+
+// Timings contains the durations measured during the exchange.
+//
+// DNS, Connect and TLS include every connection made (e.g. for redirects to other hosts).
+// TTFB and Transfer describe the final response.
+type Timings struct {
+	// time from the start until the response headers were received
+	Response time.Duration
+	// time spent resolving host names
+	DNS time.Duration
+	// time spent establishing TCP connections
+	Connect time.Duration
+	// time spent on TLS handshakes
+	TLS time.Duration
+	// time from the request being written until the first response byte arrived (i.e. server time)
+	TTFB time.Duration
+	// time from the first response byte until the body was fully read
+	Transfer time.Duration
+	// time from the start until the body was fully read
+	Total time.Duration
+}
+
+// New creates a Trace that starts now.
+func New() *Trace {
+	return &Trace{Start: time.Now()}
+}
+
+// WithTrace returns a shallow copy of req with the trace attached to its context.
+// The trace's [httptrace.ClientTrace] hooks are attached as well.
+func WithTrace(req *http.Request, t *Trace) *http.Request {
+	ctx := context.WithValue(req.Context(), traceKey{}, t)
+	return req.WithContext(httptrace.WithClientTrace(ctx, t.clientTrace()))
+}
+
+// FromRequest returns the trace attached to the request, or nil.
+func FromRequest(req *http.Request) *Trace {
+	if req == nil {
+		return nil
+	}
+	t, _ := req.Context().Value(traceKey{}).(*Trace)
+	return t
+}
+
+// FromResponse returns the trace attached to the response's request, or nil.
+func FromResponse(resp *http.Response) *Trace {
+	if resp == nil {
+		return nil
+	}
+	return FromRequest(resp.Request)
+}
+
+// MarkResponse records the time the response headers were received.
+func (t *Trace) MarkResponse() {
+	t.Response = time.Now()
+}
+
+// MarkDone records the time the response body was fully read.
+// Only the first call has any effect.
+func (t *Trace) MarkDone() {
+	if t.Done.IsZero() {
+		t.Done = time.Now()
+	}
+}
+
+// Hops returns the recorded round trips, in the order they happened.
+func (t *Trace) Hops() []Hop {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return append([]Hop(nil), t.hops...)
+}
+
+// Attempts returns how many times the request was sent (e.g. by retries); redirects are part of the same attempt.
+func (t *Trace) Attempts() int {
+	hops := t.Hops()
+	attempts := 0
+	for index := range hops {
+		if index == 0 || !hops[index-1].isRedirect() {
+			attempts++
+		}
+	}
+	return attempts
+}
+
+func (t *Trace) addHop(hop Hop) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.hops = append(t.hops, hop)
+}
+
+// RemoteAddr returns the address (ip:port) of the server that sent the final response, if known.
+func (t *Trace) RemoteAddr() string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.network.remoteAddr
+}
+
+// Timings calculates the durations; any step that has not happened yet is reported as zero.
+func (t *Trace) Timings() Timings {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return Timings{
+		Response: t.since(t.Response),
+		DNS:      t.network.dns,
+		Connect:  t.network.connect,
+		TLS:      t.network.tls,
+		TTFB:     between(t.network.wroteRequest, t.network.firstByte),
+		Transfer: between(t.network.firstByte, t.Done),
+		Total:    t.since(t.Done),
+	}
+}
+
+func (t *Trace) since(when time.Time) time.Duration {
+	if when.IsZero() {
+		return 0
+	}
+	return when.Sub(t.Start)
+}
+
+func between(from time.Time, to time.Time) time.Duration {
+	if from.IsZero() || to.IsZero() || to.Before(from) {
+		return 0
+	}
+	return to.Sub(from)
+}
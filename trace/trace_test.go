@@ -0,0 +1,125 @@
+package trace
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Trace", func() {
+	It("is attached to the request", func() {
+		// Arrange
+		t := New()
+		req, _ := http.NewRequest("GET", "http://localhost", nil)
+
+		// Act
+		traced := WithTrace(req, t)
+
+		// Assert
+		Expect(FromRequest(traced)).To(BeIdenticalTo(t))
+		Expect(FromRequest(req)).To(BeNil())
+		Expect(FromRequest(nil)).To(BeNil())
+		Expect(FromResponse(&http.Response{Request: traced})).To(BeIdenticalTo(t))
+		Expect(FromResponse(nil)).To(BeNil())
+	})
+
+	It("calculates timings", func() {
+		// Arrange
+		start := time.Now()
+		t := &Trace{Start: start, Response: start.Add(5 * time.Millisecond)}
+
+		// Act
+		before := t.Timings()
+		t.Done = start.Add(8 * time.Millisecond)
+		t.MarkDone() // must not replace the existing value
+		after := t.Timings()
+
+		// Assert
+		Expect(before).To(Equal(Timings{Response: 5 * time.Millisecond}))
+		Expect(after).To(Equal(Timings{Response: 5 * time.Millisecond, Total: 8 * time.Millisecond}))
+	})
+
+	It("records every hop", func() {
+		// Arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/first" {
+				http.Redirect(w, r, "/second", http.StatusFound)
+			}
+		}))
+		defer server.Close()
+		client := &http.Client{Transport: NewTransport(nil)}
+		t := New()
+		req, _ := http.NewRequest("GET", server.URL+"/first", nil)
+
+		// Act
+		resp, err := client.Do(WithTrace(req, t))
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		hops := t.Hops()
+		Expect(hops).To(HaveLen(2))
+		Expect(hops[0].Request.URL.Path).To(Equal("/first"))
+		Expect(hops[0].Response.StatusCode).To(Equal(http.StatusFound))
+		Expect(hops[1].Request.URL.Path).To(Equal("/second"))
+		Expect(hops[1].Response).To(BeIdenticalTo(resp))
+	})
+	DescribeTable("counts attempts",
+		func(statuses []int, expect int) {
+			// Arrange
+			t := New()
+			for _, status := range statuses {
+				resp := &http.Response{StatusCode: status, Header: http.Header{}}
+				if status >= 300 && status < 400 {
+					resp.Header.Set("Location", "/next")
+				}
+				t.addHop(Hop{Response: resp})
+			}
+
+			// Act
+			attempts := t.Attempts()
+
+			// Assert
+			Expect(attempts).To(Equal(expect))
+		},
+		Entry("no hops", []int{}, 0),
+		Entry("single request", []int{200}, 1),
+		Entry("redirects are the same attempt", []int{302, 307, 200}, 1),
+		Entry("retries are new attempts", []int{503, 503, 200}, 3),
+		Entry("retried redirects", []int{302, 503, 302, 200}, 2),
+	)
+
+	It("records network timings", func() {
+		// Arrange
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(20 * time.Millisecond)
+			w.Write([]byte("body"))
+		}))
+		defer server.Close()
+		client := server.Client()
+		client.Transport = NewTransport(client.Transport)
+		t := New()
+		req, _ := http.NewRequest("GET", server.URL, nil)
+
+		// Act
+		resp, err := client.Do(WithTrace(req, t))
+		Expect(err).ToNot(HaveOccurred())
+		t.MarkResponse()
+		io.ReadAll(resp.Body)
+		resp.Body.Close()
+		t.MarkDone()
+
+		// Assert
+		timings := t.Timings()
+		Expect(timings.DNS).To(BeZero()) // no lookup for an IP address
+		Expect(timings.Connect).To(BeNumerically(">", 0))
+		Expect(timings.TLS).To(BeNumerically(">", 0))
+		Expect(timings.TTFB).To(BeNumerically(">=", 20*time.Millisecond))
+		Expect(timings.Transfer).To(BeNumerically(">=", 0))
+		Expect(timings.Total).To(BeNumerically(">=", timings.TTFB+timings.TLS))
+	})
+})
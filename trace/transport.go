@@ -0,0 +1,44 @@
+package trace
+
+import (
+	"net/http"
+)
+
+// Hop is a single request/response round trip; redirects produce one hop each.
+//
+// For every hop except the last, the response body has already been consumed and closed
+// by the http client by the time anybody looks at it, so only the status and headers are useful.
+type Hop struct {
+	Request  *http.Request
+	Response *http.Response
+	Err      error
+}
+
+// isRedirect is true when the client follows the hop's response to the next hop
+func (h Hop) isRedirect() bool {
+	if h.Err != nil || h.Response == nil {
+		return false
+	}
+	return h.Response.StatusCode >= 300 && h.Response.StatusCode < 400 && h.Response.Header.Get("Location") != ""
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+// NewTransport wraps base (or [http.DefaultTransport] if nil) so that every round trip is
+// recorded as a [Hop] in the [Trace] attached to the request.
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if tr := FromRequest(req); tr != nil {
+		tr.addHop(Hop{Request: req, Response: resp, Err: err})
+	}
+	return resp, err
+}
@@ -2,17 +2,28 @@ package validate
 
 import (
 	"encoding/json"
+	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/keithpaterson/postal/config"
 	"github.com/keithpaterson/postal/resolver"
 
 	"github.com/keithpaterson/resweave-utils/utility/rw"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// the [[assert]] checks and [[capture]] sources that need the response body, which isn't available
+// when it is downloaded
+var (
+	bodyAssertChecks   = []string{"json", "body", "size"}
+	bodyCaptureSources = []string{"json", "regex"}
 )
 
 // ValidateConfig resolves tokens in the config data, validates the result and returns a new (valid) config object
 //
-// If validation fails, returns the original config object with the error.
+// If a required token cannot be resolved or validation fails, returns the original config object with the error.
 func ValidateConfig(cfg *config.Config) (*config.Config, error) {
 	var raw []byte
 	var err error
@@ -22,6 +33,10 @@ func ValidateConfig(cfg *config.Config) (*config.Config, error) {
 
 	res := resolver.NewResolver(cfg)
 	resolvedStr := res.Resolve(string(raw))
+	if err = res.Err(); err != nil {
+		// e.g. the OAuth2 token couldn't be fetched; don't send the request without it
+		return cfg, err
+	}
 
 	var resolved config.Config
 	if err = rw.UnmarshalJson(strings.NewReader(resolvedStr), &resolved); err != nil {
@@ -34,3 +49,21 @@ func ValidateConfig(cfg *config.Config) (*config.Config, error) {
 
 	return &resolved, nil
 }
+
+// downloadValidator rejects [[assert]] and [[capture]] entries that need the body when it is downloaded
+func downloadValidator(sl validator.StructLevel) {
+	cfg := sl.Current().Interface().(config.Config)
+	if cfg.Download.Path == "" {
+		return
+	}
+	for index, assert := range cfg.Assert {
+		if slices.Contains(bodyAssertChecks, assert.Check) {
+			sl.ReportError(assert.Check, fmt.Sprintf("Assert[%d].Check", index), "Check", "nodownload", assert.Check)
+		}
+	}
+	for index, capture := range cfg.Capture {
+		if slices.Contains(bodyCaptureSources, capture.From) {
+			sl.ReportError(capture.From, fmt.Sprintf("Capture[%d].From", index), "From", "nodownload", capture.From)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package validate
+
+import (
+	"github.com/keithpaterson/postal/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Config Validator", func() {
+	DescribeTable("downloads",
+		func(assert []config.AssertConfig, capture []config.CaptureConfig, expectErr string) {
+			// Arrange
+			cfg := config.NewConfig()
+			cfg.Request.Method = "GET"
+			cfg.Request.URL = "http://test.io/file.zip"
+			cfg.Download.Path = "file.zip"
+			cfg.Assert = assert
+			cfg.Capture = capture
+
+			// Act
+			_, err := ValidateConfig(cfg)
+
+			// Assert
+			if expectErr != "" {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(expectErr))
+			} else {
+				Expect(err).ToNot(HaveOccurred())
+			}
+		},
+		Entry("status and header checks", []config.AssertConfig{{Check: "status", Expr: "2xx"}, {Check: "header", Expr: "etag", Exists: ptr(true)}}, []config.CaptureConfig{{Name: "etag", From: "header", Expr: "etag"}}, ""),
+		Entry("json check", []config.AssertConfig{{Check: "json", Expr: "id", Exists: ptr(true)}}, nil, "Assert[0].Check"),
+		Entry("body check", []config.AssertConfig{{Check: "status", Expr: "2xx"}, {Check: "body", Contains: "ok"}}, nil, "Assert[1].Check"),
+		Entry("size check", []config.AssertConfig{{Check: "size", Min: ptr(1.0)}}, nil, "Assert[0].Check"),
+		Entry("json capture", nil, []config.CaptureConfig{{Name: "id", From: "json", Expr: "id"}}, "Capture[0].From"),
+		Entry("regex capture", nil, []config.CaptureConfig{{Name: "id", From: "regex", Expr: "id=(\\d+)"}}, "Capture[0].From"),
+	)
+
+	It("allows body checks without a download", func() {
+		// Arrange
+		cfg := config.NewConfig()
+		cfg.Request.Method = "GET"
+		cfg.Request.URL = "http://test.io/orders"
+		cfg.Assert = []config.AssertConfig{{Check: "json", Expr: "id", Exists: ptr(true)}}
+
+		// Act
+		_, err := ValidateConfig(cfg)
+
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
+
+func ptr[T any](value T) *T {
+	return &value
+}
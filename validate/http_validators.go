@@ -3,6 +3,9 @@ package validate
 import (
 	"net/http"
 
+	"github.com/keithpaterson/postal/report"
+	"github.com/keithpaterson/postal/statusmatch"
+
 	"github.com/go-playground/validator/v10"
 )
 
@@ -13,3 +16,13 @@ func httpMethodValidator(fl validator.FieldLevel) bool {
 	}
 	return false
 }
+
+func httpStatusMatchValidator(fl validator.FieldLevel) bool {
+	_, err := statusmatch.Parse(fl.Field().String())
+	return err == nil
+}
+
+func reportValidator(fl validator.FieldLevel) bool {
+	_, _, err := report.Parse(fl.Field().String())
+	return err == nil
+}
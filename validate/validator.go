@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/keithpaterson/postal/config"
+
 	"github.com/go-playground/validator/v10"
 )
 
@@ -27,5 +29,12 @@ func newValidator() *validator.Validate {
 	if err := v.RegisterValidation("method", httpMethodValidator); err != nil {
 		panic(fmt.Sprint("ERROR: failed to register 'http.method' validator", err))
 	}
+	if err := v.RegisterValidation("statusmatch", httpStatusMatchValidator); err != nil {
+		panic(fmt.Sprint("ERROR: failed to register 'statusmatch' validator", err))
+	}
+	if err := v.RegisterValidation("report", reportValidator); err != nil {
+		panic(fmt.Sprint("ERROR: failed to register 'report' validator", err))
+	}
+	v.RegisterStructValidation(downloadValidator, config.Config{})
 	return v
 }